@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -54,6 +55,18 @@ func main() {
 	tripRepo := &dbRepo.TripsRepository{DB: database}
 	mediaRepo := &dbRepo.MediaRepository{DB: database}
 	albumsTripsRepo := &dbRepo.AlbumsTripsRepository{DB: database}
+	itineraryRepo := &dbRepo.ItineraryRepository{DB: database}
+	tripMembersRepo := &dbRepo.TripMembersRepository{DB: database}
+	tripInvitationsRepo := &dbRepo.TripInvitationsRepository{DB: database}
+	tripTagsRepo := &dbRepo.TripTagsRepository{DB: database}
+	tripRevisionsRepo := &dbRepo.TripRevisionsRepository{DB: database}
+	expensesRepo := &dbRepo.ExpensesRepository{DB: database}
+	checklistsRepo := &dbRepo.ChecklistsRepository{DB: database}
+	journalRepo := &dbRepo.JournalRepository{DB: database}
+	commentsRepo := &dbRepo.CommentsRepository{DB: database}
+	tracksRepo := &dbRepo.TracksRepository{DB: database}
+	importJobsRepo := &dbRepo.ImportJobsRepository{DB: database}
+	calendarTokensRepo := &dbRepo.CalendarTokensRepository{DB: database}
 
 	// Initialize authClient
 	authClient := &service.AuthClient{BaseURL: cfg.AuthServiceUrl}
@@ -65,27 +78,109 @@ func main() {
 
 	// Initialize services
 	albumsTripsService := &service.AlbumsTripsService{AlbumsTripsRepo: albumsTripsRepo}
-	tripService := &service.TripService{TripRepo: tripRepo, Events: publisher}
+	tripService := &service.TripService{
+		TripRepo:     tripRepo,
+		MemberRepo:   tripMembersRepo,
+		TagRepo:      tripTagsRepo,
+		RevisionRepo: tripRevisionsRepo,
+		MediaRepo:    mediaRepo,
+		Minio:        minioService,
+		Events:       publisher,
+	}
 	mediaService := &service.MediaService{
 		MediaRepo:    mediaRepo,
 		MinioService: minioService,
 		Events:       publisher,
 	}
 	geocodingService := &service.GeocodingService{}
+	itineraryService := &service.ItineraryService{
+		ItineraryRepo: itineraryRepo,
+		TripService:   tripService,
+	}
+	invitationService := &service.TripInvitationService{
+		InvitationRepo: tripInvitationsRepo,
+		TripService:    tripService,
+		Events:         publisher,
+	}
+	statsService := &service.TripStatsService{
+		TripService:  tripService,
+		MediaService: mediaService,
+	}
+	trashService := &service.TrashService{
+		TripService:  tripService,
+		MediaService: mediaService,
+		Retention:    cfg.TrashRetention,
+	}
+	go trashService.RunPurge(time.Hour)
+	expenseService := &service.ExpenseService{
+		ExpenseRepo: expensesRepo,
+		TripService: tripService,
+		AdminIDs:    cfg.AdminUserIDs,
+	}
+	checklistService := &service.ChecklistService{
+		ChecklistRepo: checklistsRepo,
+		TripService:   tripService,
+	}
+	journalService := &service.JournalService{
+		JournalRepo:  journalRepo,
+		TripService:  tripService,
+		MediaService: mediaService,
+	}
+	commentService := &service.CommentService{
+		CommentRepo:  commentsRepo,
+		TripService:  tripService,
+		MediaService: mediaService,
+		Events:       publisher,
+	}
+	exportService := &service.TripExportService{
+		TripService:  tripService,
+		MediaService: mediaService,
+	}
+	trackService := &service.TrackService{
+		TrackRepo:    tracksRepo,
+		TripService:  tripService,
+		MediaService: mediaService,
+	}
+	importService := &service.ImportService{
+		ImportRepo:   importJobsRepo,
+		TripService:  tripService,
+		MediaService: mediaService,
+	}
+	go importService.RunInterruptedJobsCheck(time.Minute)
+	calendarService := &service.CalendarService{
+		CalendarRepo:  calendarTokensRepo,
+		ItineraryRepo: itineraryRepo,
+		TripService:   tripService,
+	}
 
 	// Initialize controllers
 	tripHandler := &controller.TripController{
-		TripService:      tripService,
-		MediaService:     mediaService,
-		AuthClient:       authClient,
-		ProfileClient:    profileClient,
-		AlbumTripService: albumsTripsService,
-		LikesClient:      &service.LikesClient{BaseURL: "https://actions.nostos-globe.me"}, // Add this line
+		TripService:       tripService,
+		MediaService:      mediaService,
+		AuthClient:        authClient,
+		ProfileClient:     profileClient,
+		AlbumTripService:  albumsTripsService,
+		LikesClient:       &service.LikesClient{BaseURL: "https://actions.nostos-globe.me"}, // Add this line
+		ItineraryService:  itineraryService,
+		InvitationService: invitationService,
+		StatsService:      statsService,
+		TrashService:      trashService,
+		ExpenseService:    expenseService,
+		ChecklistService:  checklistService,
+		JournalService:    journalService,
+		CommentService:    commentService,
+		ExportService:     exportService,
+		TrackService:      trackService,
+		ImportService:     importService,
+		CalendarService:   calendarService,
 	}
 	mediaHandler := &controller.MediaController{
 		MediaService:     mediaService,
+		TripService:      tripService,
 		AuthClient:       authClient,
 		GeocodingService: geocodingService,
+		CommentService:   commentService,
+		TrackService:     trackService,
 	}
 
 	// Initialize Gin
@@ -97,13 +192,80 @@ func main() {
 		api.POST("/", tripHandler.CreateTrip)
 		api.GET("/", tripHandler.GetAllTrips)
 		api.POST("/search", tripHandler.SearchTrips)
+		api.POST("/import-track", tripHandler.ImportTrackTrip)
+		api.POST("/imports", tripHandler.ImportArchive)
+		api.GET("/imports", tripHandler.GetImports)
+		api.GET("/imports/:job_id", tripHandler.GetImport)
+		api.GET("/calendar/tokens", tripHandler.GetCalendarTokens)
+		api.POST("/calendar/tokens", tripHandler.CreateCalendarToken)
+		api.DELETE("/calendar/tokens/:token_id", tripHandler.RevokeCalendarToken)
+		api.GET("/calendar/:token", tripHandler.GetCalendarFeed)
 		api.GET("/public", tripHandler.GetPublicTrips)
 		api.GET("/myTrips", tripHandler.GetMyTrips)
 		api.GET("/following", tripHandler.GetFollowedUsersTrips)
 		api.GET("/user/:id", tripHandler.GetTripsByUserID)
+		api.GET("/user/:id/stats", tripHandler.GetUserStats)
 		api.GET("/myLikedTrips", tripHandler.GetMyLikedTrips)
+		api.GET("/shared", tripHandler.GetSharedTrips)
+		api.GET("/invitations", tripHandler.GetMyInvitations)
+		api.GET("/categories", tripHandler.GetTripCategories)
+		api.GET("/trash", tripHandler.GetTrash)
+		api.GET("/exchange-rates", tripHandler.GetExchangeRates)
+		api.PUT("/exchange-rates/:currency", tripHandler.SetExchangeRate)
+		api.GET("/checklist-templates", tripHandler.GetChecklistTemplates)
+		api.POST("/checklist-templates", tripHandler.CreateChecklistTemplate)
+		api.DELETE("/checklist-templates/:template_id", tripHandler.DeleteChecklistTemplate)
+		api.POST("/trash/:id/restore", tripHandler.RestoreTrip)
+		api.POST("/trash/media/:media_id/restore", tripHandler.RestoreMedia)
+		api.GET("/tags/popular", tripHandler.GetPopularTags)
+		api.GET("/tags/:tag", tripHandler.GetTripsByTag)
+		api.POST("/invitations/:invitation_id/respond", tripHandler.RespondToInvitation)
 		api.GET("/:id", tripHandler.GetTripByID)
+		api.PATCH("/:id", tripHandler.PatchTrip)
 		api.GET("/:id/locations", tripHandler.GetLocationsByTripID)
+		api.GET("/:id/itinerary", tripHandler.GetItinerary)
+		api.POST("/:id/itinerary/days", tripHandler.CreateItineraryDay)
+		api.DELETE("/:id/itinerary/days/:day_id", tripHandler.DeleteItineraryDay)
+		api.POST("/:id/itinerary/days/:day_id/stops", tripHandler.CreateItineraryStop)
+		api.PUT("/:id/itinerary/days/:day_id/stops/order", tripHandler.ReorderItineraryStops)
+		api.DELETE("/:id/itinerary/stops/:stop_id", tripHandler.DeleteItineraryStop)
+		api.GET("/:id/members", tripHandler.GetTripMembers)
+		api.POST("/:id/members", tripHandler.AddTripMember)
+		api.DELETE("/:id/members/:user_id", tripHandler.RemoveTripMember)
+		api.POST("/:id/invitations", tripHandler.InviteToTrip)
+		api.PUT("/:id/cover", tripHandler.SetTripCover)
+		api.GET("/:id/stats", tripHandler.GetTripStats)
+		api.GET("/:id/export.gpx", tripHandler.ExportTripGPX)
+		api.GET("/:id/export.geojson", tripHandler.ExportTripGeoJSON)
+		api.GET("/:id/export.kml", tripHandler.ExportTripKML)
+		api.GET("/:id/archive.zip", tripHandler.ExportTripArchive)
+		api.GET("/:id/tracks", tripHandler.GetTripTracks)
+		api.POST("/:id/tracks", tripHandler.AddTripTrack)
+		api.DELETE("/:id/tracks/:track_id", tripHandler.DeleteTripTrack)
+		api.POST("/:id/clone", tripHandler.CloneTrip)
+		api.GET("/:id/history", tripHandler.GetTripHistory)
+		api.POST("/:id/revert/:revision", tripHandler.RevertTripRevision)
+		api.GET("/:id/expenses", tripHandler.GetTripExpenses)
+		api.POST("/:id/expenses", tripHandler.CreateTripExpense)
+		api.GET("/:id/expenses/summary", tripHandler.GetTripExpenseSummary)
+		api.DELETE("/:id/expenses/:expense_id", tripHandler.DeleteTripExpense)
+		api.PUT("/:id/budget", tripHandler.SetTripBudget)
+		api.GET("/:id/checklists", tripHandler.GetChecklists)
+		api.POST("/:id/checklists", tripHandler.CreateChecklist)
+		api.DELETE("/:id/checklists/:checklist_id", tripHandler.DeleteChecklist)
+		api.POST("/:id/checklists/:checklist_id/items", tripHandler.CreateChecklistItem)
+		api.PUT("/:id/checklists/:checklist_id/items/order", tripHandler.ReorderChecklistItems)
+		api.PATCH("/:id/checklists/:checklist_id/items/:item_id", tripHandler.UpdateChecklistItem)
+		api.DELETE("/:id/checklists/:checklist_id/items/:item_id", tripHandler.DeleteChecklistItem)
+		api.GET("/:id/journal", tripHandler.GetJournal)
+		api.POST("/:id/journal", tripHandler.CreateJournalEntry)
+		api.GET("/:id/journal/:entry_id", tripHandler.GetJournalEntry)
+		api.PUT("/:id/journal/:entry_id", tripHandler.UpdateJournalEntry)
+		api.DELETE("/:id/journal/:entry_id", tripHandler.DeleteJournalEntry)
+		api.GET("/:id/comments", tripHandler.GetTripComments)
+		api.POST("/:id/comments", tripHandler.CreateTripComment)
+		api.PUT("/:id/comments/:comment_id", tripHandler.EditTripComment)
+		api.DELETE("/:id/comments/:comment_id", tripHandler.DeleteTripComment)
 		api.PUT("/update", tripHandler.UpdateTrip)
 		api.DELETE("/delete/:id", tripHandler.DeleteTrip)
 	}
@@ -120,6 +282,10 @@ func main() {
 		mediaApi.GET("/:media_id/visibility", mediaHandler.GetMediaVisibility)
 		mediaApi.PUT("/:media_id/visibility", mediaHandler.ChangeMediaVisibility)
 		mediaApi.GET("/:media_id/location", mediaHandler.GetLocationByMediaID)
+		mediaApi.GET("/:media_id/comments", mediaHandler.GetMediaComments)
+		mediaApi.POST("/:media_id/comments", mediaHandler.CreateMediaComment)
+		mediaApi.PUT("/:media_id/comments/:comment_id", mediaHandler.EditMediaComment)
+		mediaApi.DELETE("/:media_id/comments/:comment_id", mediaHandler.DeleteMediaComment)
 		mediaApi.GET("/trip/:trip_id", mediaHandler.GetMediaByTripID)
 	}
 
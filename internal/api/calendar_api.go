@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateCalendarToken returns a new feed URL. The token in it is only shown
+// here.
+func (c *TripController) CreateCalendarToken(ctx *gin.Context) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	value, token, err := c.CalendarService.CreateToken(userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to create calendar token for user %d - %v\n", userID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to create calendar token"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"token_id":   token.TokenID,
+		"token":      value,
+		"url":        fmt.Sprintf("webcal://%s/api/trips/calendar/%s.ics", ctx.Request.Host, value),
+		"created_at": token.CreatedAt,
+	})
+}
+
+func (c *TripController) GetCalendarTokens(ctx *gin.Context) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	tokens, err := c.CalendarService.GetTokens(userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get calendar tokens of user %d - %v\n", userID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve calendar tokens"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+func (c *TripController) RevokeCalendarToken(ctx *gin.Context) {
+	tokenID, err := strconv.ParseInt(ctx.Param("token_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid token ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.CalendarService.RevokeToken(tokenID, userID); err != nil {
+		fmt.Printf("Error: Failed to revoke calendar token %d - %v\n", tokenID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to revoke calendar token"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "calendar token revoked successfully"})
+}
+
+// GetCalendarFeed serves the iCalendar feed of the user the token belongs to.
+// Calendar apps can't send the auth cookie, the token is the credential.
+func (c *TripController) GetCalendarFeed(ctx *gin.Context) {
+	value, ok := strings.CutSuffix(ctx.Param("token"), ".ics")
+	if !ok || value == "" {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+		return
+	}
+
+	calendar, err := c.CalendarService.GetFeed(value)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+			return
+		}
+		fmt.Printf("Error: Failed to build calendar feed - %v\n", err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to build calendar"})
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=300")
+	ctx.Data(http.StatusOK, models.ICalendarContentType, calendar.Encode())
+}
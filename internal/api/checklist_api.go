@@ -0,0 +1,378 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *TripController) GetChecklists(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	checklists, err := c.ChecklistService.GetChecklists(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get checklists for trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve checklists"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"trip_id": tripID, "checklists": checklists})
+}
+
+func (c *TripController) CreateChecklist(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	// Either a title, a template or both
+	var req struct {
+		Title      string `json:"title"`
+		TemplateID *int64 `json:"template_id"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	checklist, err := c.ChecklistService.CreateChecklist(tripID, userID, req.Title, req.TemplateID)
+	if err != nil {
+		fmt.Printf("Error: Failed to create checklist for trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, checklist)
+}
+
+func (c *TripController) DeleteChecklist(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	checklistID, err := strconv.ParseInt(ctx.Param("checklist_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid checklist ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.ChecklistService.DeleteChecklist(tripID, checklistID, userID); err != nil {
+		fmt.Printf("Error: Failed to delete checklist %d - %v\n", checklistID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to delete checklist"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "checklist deleted successfully"})
+}
+
+func (c *TripController) CreateChecklistItem(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	checklistID, err := strconv.ParseInt(ctx.Param("checklist_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid checklist ID"})
+		return
+	}
+
+	var req struct {
+		Text       string `json:"text" binding:"required"`
+		AssigneeID *uint  `json:"assignee_id"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := c.ChecklistService.CreateItem(tripID, checklistID, userID, models.ChecklistItem{
+		Text:       req.Text,
+		AssigneeID: req.AssigneeID,
+	})
+	if err != nil {
+		fmt.Printf("Error: Failed to create item for checklist %d - %v\n", checklistID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, item)
+}
+
+func (c *TripController) UpdateChecklistItem(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	checklistID, err := strconv.ParseInt(ctx.Param("checklist_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid checklist ID"})
+		return
+	}
+
+	itemID, err := strconv.ParseInt(ctx.Param("item_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	var update models.ChecklistItemUpdate
+	if err := ctx.ShouldBindJSON(&update); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := c.ChecklistService.UpdateItem(tripID, checklistID, itemID, userID, update)
+	if err != nil {
+		fmt.Printf("Error: Failed to update checklist item %d - %v\n", itemID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+
+func (c *TripController) ReorderChecklistItems(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	checklistID, err := strconv.ParseInt(ctx.Param("checklist_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid checklist ID"})
+		return
+	}
+
+	var req struct {
+		ItemIDs []int64 `json:"item_ids" binding:"required"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, err := c.ChecklistService.ReorderItems(tripID, checklistID, userID, req.ItemIDs)
+	if err != nil {
+		fmt.Printf("Error: Failed to reorder items of checklist %d - %v\n", checklistID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "items reordered successfully", "items": items})
+}
+
+func (c *TripController) DeleteChecklistItem(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	checklistID, err := strconv.ParseInt(ctx.Param("checklist_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid checklist ID"})
+		return
+	}
+
+	itemID, err := strconv.ParseInt(ctx.Param("item_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.ChecklistService.DeleteItem(tripID, checklistID, itemID, userID); err != nil {
+		fmt.Printf("Error: Failed to delete checklist item %d - %v\n", itemID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to delete checklist item"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "checklist item deleted successfully"})
+}
+
+func (c *TripController) GetChecklistTemplates(ctx *gin.Context) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	templates, err := c.ChecklistService.GetTemplates(userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get checklist templates - %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve checklist templates"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+func (c *TripController) CreateChecklistTemplate(ctx *gin.Context) {
+	// Items are taken from the body or copied from an existing checklist
+	var req struct {
+		Title       string   `json:"title"`
+		Items       []string `json:"items"`
+		ChecklistID *int64   `json:"checklist_id"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := c.ChecklistService.CreateTemplate(userID, req.Title, req.Items, req.ChecklistID)
+	if err != nil {
+		fmt.Printf("Error: Failed to create checklist template - %v\n", err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, template)
+}
+
+func (c *TripController) DeleteChecklistTemplate(ctx *gin.Context) {
+	templateID, err := strconv.ParseInt(ctx.Param("template_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.ChecklistService.DeleteTemplate(templateID, userID); err != nil {
+		fmt.Printf("Error: Failed to delete checklist template %d - %v\n", templateID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to delete checklist template"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "checklist template deleted successfully"})
+}
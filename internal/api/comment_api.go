@@ -0,0 +1,258 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type commentRequest struct {
+	Body     string `json:"body" binding:"required"`
+	ParentID *int64 `json:"parent_id"`
+}
+
+func (c *TripController) GetTripComments(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	userID, ok := authenticate(ctx, c.AuthClient)
+	if !ok {
+		return
+	}
+
+	listComments(ctx, c.CommentService, tripID, nil, userID)
+}
+
+func (c *TripController) CreateTripComment(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	userID, ok := authenticate(ctx, c.AuthClient)
+	if !ok {
+		return
+	}
+
+	createComment(ctx, c.CommentService, tripID, nil, userID)
+}
+
+func (c *TripController) EditTripComment(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	commentID, err := strconv.ParseInt(ctx.Param("comment_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return
+	}
+
+	userID, ok := authenticate(ctx, c.AuthClient)
+	if !ok {
+		return
+	}
+
+	editComment(ctx, c.CommentService, tripID, nil, commentID, userID)
+}
+
+func (c *TripController) DeleteTripComment(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	commentID, err := strconv.ParseInt(ctx.Param("comment_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return
+	}
+
+	userID, ok := authenticate(ctx, c.AuthClient)
+	if !ok {
+		return
+	}
+
+	deleteComment(ctx, c.CommentService, tripID, nil, commentID, userID)
+}
+
+func (c *MediaController) GetMediaComments(ctx *gin.Context) {
+	tripID, mediaID, ok := c.commentMedia(ctx)
+	if !ok {
+		return
+	}
+
+	userID, ok := authenticate(ctx, c.AuthClient)
+	if !ok {
+		return
+	}
+
+	listComments(ctx, c.CommentService, tripID, &mediaID, userID)
+}
+
+func (c *MediaController) CreateMediaComment(ctx *gin.Context) {
+	tripID, mediaID, ok := c.commentMedia(ctx)
+	if !ok {
+		return
+	}
+
+	userID, ok := authenticate(ctx, c.AuthClient)
+	if !ok {
+		return
+	}
+
+	createComment(ctx, c.CommentService, tripID, &mediaID, userID)
+}
+
+func (c *MediaController) EditMediaComment(ctx *gin.Context) {
+	tripID, mediaID, ok := c.commentMedia(ctx)
+	if !ok {
+		return
+	}
+
+	commentID, err := strconv.ParseInt(ctx.Param("comment_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return
+	}
+
+	userID, ok := authenticate(ctx, c.AuthClient)
+	if !ok {
+		return
+	}
+
+	editComment(ctx, c.CommentService, tripID, &mediaID, commentID, userID)
+}
+
+func (c *MediaController) DeleteMediaComment(ctx *gin.Context) {
+	tripID, mediaID, ok := c.commentMedia(ctx)
+	if !ok {
+		return
+	}
+
+	commentID, err := strconv.ParseInt(ctx.Param("comment_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return
+	}
+
+	userID, ok := authenticate(ctx, c.AuthClient)
+	if !ok {
+		return
+	}
+
+	deleteComment(ctx, c.CommentService, tripID, &mediaID, commentID, userID)
+}
+
+// commentMedia parses the media ID of the route and finds its trip.
+func (c *MediaController) commentMedia(ctx *gin.Context) (int, int64, bool) {
+	mediaID, err := strconv.ParseInt(ctx.Param("media_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid media ID"})
+		return 0, 0, false
+	}
+
+	tripID, err := c.CommentService.MediaTripID(mediaID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get media %d - %v\n", mediaID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve media"})
+		return 0, 0, false
+	}
+
+	return tripID, mediaID, true
+}
+
+// authenticate resolves the user of the auth_token cookie, answering 401
+// when there is none.
+func authenticate(ctx *gin.Context, authClient *service.AuthClient) (uint, bool) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return 0, false
+	}
+
+	userID, err := authClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return 0, false
+	}
+
+	return userID, true
+}
+
+func listComments(ctx *gin.Context, comments *service.CommentService, tripID int, mediaID *int64, userID uint) {
+	limit := 0
+	if value := ctx.Query("limit"); value != "" {
+		l, err := strconv.Atoi(value)
+		if err != nil || l <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = l
+	}
+
+	page, err := comments.GetComments(tripID, mediaID, userID, limit, ctx.Query("cursor"))
+	if err != nil {
+		fmt.Printf("Error: Failed to get comments of trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve comments"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+func createComment(ctx *gin.Context, comments *service.CommentService, tripID int, mediaID *int64, userID uint) {
+	var req commentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := comments.CreateComment(tripID, mediaID, userID, req.ParentID, req.Body)
+	if err != nil {
+		fmt.Printf("Error: Failed to create comment on trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, comment)
+}
+
+func editComment(ctx *gin.Context, comments *service.CommentService, tripID int, mediaID *int64, commentID int64, userID uint) {
+	var req struct {
+		Body string `json:"body" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := comments.EditComment(tripID, mediaID, commentID, userID, req.Body)
+	if err != nil {
+		fmt.Printf("Error: Failed to edit comment %d - %v\n", commentID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, comment)
+}
+
+func deleteComment(ctx *gin.Context, comments *service.CommentService, tripID int, mediaID *int64, commentID int64, userID uint) {
+	if err := comments.DeleteComment(tripID, mediaID, commentID, userID); err != nil {
+		fmt.Printf("Error: Failed to delete comment %d - %v\n", commentID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to delete comment"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "comment deleted successfully"})
+}
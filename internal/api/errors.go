@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"errors"
+	"main/internal/service"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// errorStatus maps the errors returned by the services to an HTTP status code.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNotAuthorized):
+		return http.StatusForbidden
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrVersionConflict):
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setETag(ctx *gin.Context, version int) {
+	ctx.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// requireIfMatch reads the version the client based its update on from the
+// If-Match header. "*" matches any version and is returned as 0. When the
+// header is missing or malformed the response is written and ok is false.
+func requireIfMatch(ctx *gin.Context) (version int, ok bool) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header with the current ETag is required"})
+		return 0, false
+	}
+	return parseIfMatch(ctx, header)
+}
+
+func parseIfMatch(ctx *gin.Context, header string) (int, bool) {
+	if header == "*" {
+		return 0, true
+	}
+	value := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		unquoted = value
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header"})
+		return 0, false
+	}
+	return version, true
+}
+
+// tripConflict answers a stale write with the trip as it is now stored.
+func (c *TripController) tripConflict(ctx *gin.Context, tripID int, userID uint) {
+	current, err := c.TripService.GetTripDetails(strconv.Itoa(tripID), userID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve trip"})
+		return
+	}
+	setETag(ctx, current.Version)
+	ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": "the trip was modified by someone else", "trip": current})
+}
+
+// mediaConflict answers a stale write with the media as it is now stored.
+func (c *MediaController) mediaConflict(ctx *gin.Context, mediaID int64) {
+	current, err := c.MediaService.GetMediaByID(mediaID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve media"})
+		return
+	}
+	setETag(ctx, current.Version)
+	ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": "the media was modified by someone else", "media": current})
+}
@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// expenseRequest holds the fields a client may set on a new expense; the
+// IDs and audit fields are filled in by the server.
+type expenseRequest struct {
+	Amount      models.Money `json:"amount"`
+	Currency    string       `json:"currency"`
+	Category    string       `json:"category"`
+	Description string       `json:"description"`
+	Date        models.Date  `json:"date"`
+	PaidBy      uint         `json:"paid_by"`
+	SplitAmong  []uint       `json:"split_among"`
+}
+
+func (r expenseRequest) expense() models.Expense {
+	return models.Expense{
+		Amount:      r.Amount,
+		Currency:    r.Currency,
+		Category:    r.Category,
+		Description: r.Description,
+		Date:        r.Date,
+		PaidBy:      r.PaidBy,
+		SplitAmong:  r.SplitAmong,
+	}
+}
+
+func (c *TripController) GetTripExpenses(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	expenses, err := c.ExpenseService.GetExpenses(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get expenses of trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve expenses"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"trip_id": tripID, "expenses": expenses})
+}
+
+func (c *TripController) CreateTripExpense(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	var req expenseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := c.ExpenseService.CreateExpense(tripID, userID, req.expense())
+	if err != nil {
+		fmt.Printf("Error: Failed to create expense for trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+func (c *TripController) DeleteTripExpense(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	expenseID, err := strconv.ParseInt(ctx.Param("expense_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.ExpenseService.DeleteExpense(tripID, expenseID, userID); err != nil {
+		fmt.Printf("Error: Failed to delete expense %d - %v\n", expenseID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "expense deleted successfully"})
+}
+
+func (c *TripController) GetTripExpenseSummary(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	summary, err := c.ExpenseService.GetSummary(tripID, userID, ctx.Query("currency"))
+	if err != nil {
+		fmt.Printf("Error: Failed to summarize expenses of trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, summary)
+}
+
+func (c *TripController) SetTripBudget(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	var req struct {
+		Amount   models.Money `json:"amount"`
+		Currency string       `json:"currency" binding:"required"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	budget, err := c.ExpenseService.SetBudget(tripID, userID, req.Amount, req.Currency)
+	if err != nil {
+		fmt.Printf("Error: Failed to set budget of trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, budget)
+}
+
+func (c *TripController) GetExchangeRates(ctx *gin.Context) {
+	rates, err := c.ExpenseService.GetExchangeRates()
+	if err != nil {
+		fmt.Printf("Error: Failed to get exchange rates - %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve exchange rates"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"base": models.BaseCurrency, "rates": rates})
+}
+
+func (c *TripController) SetExchangeRate(ctx *gin.Context) {
+	var req struct {
+		Rate float64 `json:"rate" binding:"required"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rate, err := c.ExpenseService.SetExchangeRate(ctx.Param("currency"), req.Rate, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to set exchange rate - %v\n", err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rate)
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"main/internal/models"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportArchive starts the import of a trip archive or a Google Takeout
+// export. The import runs in the background, its progress is polled with
+// GetImport.
+func (c *TripController) ImportArchive(ctx *gin.Context) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	// Leave room for the other parts of the form
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, models.MaxImportArchiveSize+1<<20)
+	archivePath, visibility, err := receiveImportArchive(ctx.Request)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("archives are limited to %d GB", models.MaxImportArchiveSize>>30)})
+		case errors.Is(err, errNoArchive):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "no archive provided"})
+		default:
+			fmt.Printf("Error: Failed to receive import archive - %v\n", err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read the archive upload"})
+		}
+		return
+	}
+
+	job, err := c.ImportService.StartImport(userID, archivePath, visibility)
+	if err != nil {
+		os.Remove(archivePath)
+		fmt.Printf("Error: Failed to start import for user %d - %v\n", userID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Location", fmt.Sprintf("/api/trips/imports/%d", job.JobID))
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+var errNoArchive = errors.New("no archive provided")
+
+// receiveImportArchive streams the archive part of the multipart form straight
+// to a temporary file, which the import then owns, instead of letting the
+// form parser spool it first. The form's visibility is returned with it.
+func receiveImportArchive(req *http.Request) (archivePath string, visibility models.VisibilityEnum, err error) {
+	defer func() {
+		if err != nil && archivePath != "" {
+			os.Remove(archivePath)
+			archivePath = ""
+		}
+	}()
+
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return archivePath, "", err
+		}
+
+		switch part.FormName() {
+		case "archive":
+			if archivePath != "" {
+				err = errors.New("more than one archive provided")
+				break
+			}
+			archivePath, err = saveImportArchive(part)
+		case "visibility":
+			var value []byte
+			value, err = io.ReadAll(io.LimitReader(part, 32))
+			visibility = models.VisibilityEnum(strings.TrimSpace(string(value)))
+		}
+		part.Close()
+		if err != nil {
+			return archivePath, "", err
+		}
+	}
+
+	if archivePath == "" {
+		return "", "", errNoArchive
+	}
+	return archivePath, visibility, nil
+}
+
+func saveImportArchive(part io.Reader) (string, error) {
+	archive, err := os.CreateTemp("", "trip-import-*.zip")
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(archive, part)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(archive.Name())
+		return "", err
+	}
+	return archive.Name(), nil
+}
+
+func (c *TripController) GetImports(ctx *gin.Context) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	jobs, err := c.ImportService.GetJobs(userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get imports of user %d - %v\n", userID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve imports"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"imports": jobs})
+}
+
+func (c *TripController) GetImport(ctx *gin.Context) {
+	jobID, err := strconv.ParseInt(ctx.Param("job_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid import ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	job, err := c.ImportService.GetJob(jobID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get import %d - %v\n", jobID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve import"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *TripController) GetItinerary(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	days, err := c.ItineraryService.GetItinerary(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get itinerary for trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve itinerary"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"trip_id": tripID, "days": days})
+}
+
+func (c *TripController) CreateItineraryDay(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	var req struct {
+		Date  string `json:"date" binding:"required"`
+		Title string `json:"title"`
+		Notes string `json:"notes"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	date, err := models.ParseDate(req.Date)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	day, err := c.ItineraryService.CreateDay(tripID, userID, models.ItineraryDay{
+		Date:  date,
+		Title: req.Title,
+		Notes: req.Notes,
+	})
+	if err != nil {
+		fmt.Printf("Error: Failed to create itinerary day for trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, day)
+}
+
+func (c *TripController) DeleteItineraryDay(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	dayID, err := strconv.ParseInt(ctx.Param("day_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid day ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.ItineraryService.DeleteDay(tripID, dayID, userID); err != nil {
+		fmt.Printf("Error: Failed to delete itinerary day %d - %v\n", dayID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to delete itinerary day"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "itinerary day deleted successfully"})
+}
+
+func (c *TripController) CreateItineraryStop(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	dayID, err := strconv.ParseInt(ctx.Param("day_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid day ID"})
+		return
+	}
+
+	var req struct {
+		Name         string     `json:"name" binding:"required"`
+		LocationID   *int64     `json:"location_id"`
+		PlannedStart *time.Time `json:"planned_start"`
+		PlannedEnd   *time.Time `json:"planned_end"`
+		Notes        string     `json:"notes"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stop, err := c.ItineraryService.CreateStop(tripID, dayID, userID, models.ItineraryStop{
+		Name:         req.Name,
+		LocationID:   req.LocationID,
+		PlannedStart: req.PlannedStart,
+		PlannedEnd:   req.PlannedEnd,
+		Notes:        req.Notes,
+	})
+	if err != nil {
+		fmt.Printf("Error: Failed to create itinerary stop for day %d - %v\n", dayID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, stop)
+}
+
+func (c *TripController) ReorderItineraryStops(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	dayID, err := strconv.ParseInt(ctx.Param("day_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid day ID"})
+		return
+	}
+
+	var req struct {
+		StopIDs []int64 `json:"stop_ids" binding:"required"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stops, err := c.ItineraryService.ReorderStops(tripID, dayID, userID, req.StopIDs)
+	if err != nil {
+		fmt.Printf("Error: Failed to reorder stops for day %d - %v\n", dayID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "stops reordered successfully", "stops": stops})
+}
+
+func (c *TripController) DeleteItineraryStop(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	stopID, err := strconv.ParseInt(ctx.Param("stop_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid stop ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.ItineraryService.DeleteStop(tripID, stopID, userID); err != nil {
+		fmt.Printf("Error: Failed to delete itinerary stop %d - %v\n", stopID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to delete itinerary stop"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "itinerary stop deleted successfully"})
+}
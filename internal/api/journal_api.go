@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type journalEntryRequest struct {
+	Date       string                `json:"date" binding:"required"`
+	Title      string                `json:"title"`
+	Body       string                `json:"body" binding:"required"`
+	LocationID *int64                `json:"location_id"`
+	Visibility models.VisibilityEnum `json:"visibility"`
+}
+
+func (r journalEntryRequest) entry() (models.JournalEntry, error) {
+	date, err := models.ParseDate(r.Date)
+	if err != nil {
+		return models.JournalEntry{}, err
+	}
+	return models.JournalEntry{
+		Date:       date,
+		Title:      r.Title,
+		Body:       r.Body,
+		LocationID: r.LocationID,
+		Visibility: r.Visibility,
+	}, nil
+}
+
+func (c *TripController) GetJournal(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	var date *models.Date
+	if value := ctx.Query("date"); value != "" {
+		parsed, err := models.ParseDate(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		date = &parsed
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	entries, err := c.JournalService.GetEntries(tripID, userID, date)
+	if err != nil {
+		fmt.Printf("Error: Failed to get journal of trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve journal"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"trip_id": tripID, "entries": entries})
+}
+
+func (c *TripController) GetJournalEntry(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	entryID, err := strconv.ParseInt(ctx.Param("entry_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entry ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	entry, err := c.JournalService.GetEntry(tripID, entryID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get journal entry %d - %v\n", entryID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve journal entry"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entry)
+}
+
+func (c *TripController) CreateJournalEntry(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	var req journalEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := req.entry()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := c.JournalService.CreateEntry(tripID, userID, entry)
+	if err != nil {
+		fmt.Printf("Error: Failed to create journal entry for trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+func (c *TripController) UpdateJournalEntry(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	entryID, err := strconv.ParseInt(ctx.Param("entry_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entry ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	var req journalEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := req.entry()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := c.JournalService.UpdateEntry(tripID, entryID, userID, entry)
+	if err != nil {
+		fmt.Printf("Error: Failed to update journal entry %d - %v\n", entryID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (c *TripController) DeleteJournalEntry(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	entryID, err := strconv.ParseInt(ctx.Param("entry_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid entry ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.JournalService.DeleteEntry(tripID, entryID, userID); err != nil {
+		fmt.Printf("Error: Failed to delete journal entry %d - %v\n", entryID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to delete journal entry"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "journal entry deleted successfully"})
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"main/internal/models"
 	"main/internal/service"
@@ -13,8 +14,11 @@ import (
 
 type MediaController struct {
 	MediaService     *service.MediaService
+	TripService      *service.TripService
 	AuthClient       *service.AuthClient
 	GeocodingService *service.GeocodingService
+	CommentService   *service.CommentService
+	TrackService     *service.TrackService
 }
 
 func (c *MediaController) UploadMedia(ctx *gin.Context) {
@@ -50,6 +54,25 @@ func (c *MediaController) UploadMedia(ctx *gin.Context) {
     }
     fmt.Printf("Authenticated user ID: %d\n", userID)
 
+    // Only owners and editors can add media to a trip
+    trip, err := c.TripService.GetTripByID(strconv.FormatInt(tripID, 10))
+    if err != nil {
+        fmt.Printf("Error: Trip not found - %v\n", err)
+        ctx.JSON(http.StatusNotFound, gin.H{"error": "trip not found"})
+        return
+    }
+    canEdit, err := c.TripService.CanEditTrip(trip, userID)
+    if err != nil {
+        fmt.Printf("Error: Failed to check trip role - %v\n", err)
+        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check trip permissions"})
+        return
+    }
+    if !canEdit {
+        fmt.Printf("User %d is not allowed to upload to trip %d\n", userID, tripID)
+        ctx.JSON(http.StatusForbidden, gin.H{"error": "not allowed to upload media to this trip"})
+        return
+    }
+
     // Get form values
     visibility := models.VisibilityEnum(ctx.Request.FormValue("visibility"))
     if visibility == "" {
@@ -58,6 +81,16 @@ func (c *MediaController) UploadMedia(ctx *gin.Context) {
     }
     fmt.Printf("Media visibility set to: %s\n", visibility)
 
+    // The camera's UTC offset, for photos whose EXIF date does not carry one
+    var utcOffset *time.Location
+    if offset := ctx.Request.FormValue("utc_offset"); offset != "" {
+        utcOffset, err = models.ParseUTCOffset(offset)
+        if err != nil {
+            ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+    }
+
     // Handle file upload
     file, header, err := ctx.Request.FormFile("media")
     if err != nil {
@@ -71,11 +104,21 @@ func (c *MediaController) UploadMedia(ctx *gin.Context) {
     // Extract metadata
     fmt.Println("Attempting to extract metadata from file")
     metadata, err := c.MediaService.ExtractMetadata(file, header)
+    if metadata != nil && utcOffset != nil {
+        metadata.SetCaptureOffset(utcOffset)
+    }
     requiresManualLocation := false
     if err != nil {
         if err.Error() == "MANUAL_LOCATION_REQUIRED" {
             requiresManualLocation = true
-            fmt.Println("Media requires manual location input")
+            // Media without GPS may still be placed on the trip's GPS tracks
+            if metadata.Latitude == 0 && metadata.Longitude == 0 && c.TrackService.LocateByTrack(int(tripID), metadata) {
+                requiresManualLocation = metadata.RequiresManualLocation
+                fmt.Printf("Located media from the trip's tracks at %f, %f\n", metadata.Latitude, metadata.Longitude)
+            }
+            if requiresManualLocation {
+                fmt.Println("Media requires manual location input")
+            }
         } else {
             fmt.Printf("Warning: Failed to extract metadata: %v\n", err)
         }
@@ -205,14 +248,26 @@ func (c *MediaController) AddMetadataToMedia(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
 	// Update media with new metadata
 	err = c.MediaService.UpdateMediaMetadata(mediaID, int64(userID), metadata.Latitude,
-		metadata.Longitude, metadata.Altitude)
+		metadata.Longitude, metadata.Altitude, version)
+	if errors.Is(err, service.ErrVersionConflict) {
+		c.mediaConflict(ctx, mediaID)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update metadata"})
 		return
 	}
 
+	if media, err := c.MediaService.GetMediaByID(mediaID); err == nil {
+		setETag(ctx, media.Version)
+	}
 	ctx.JSON(http.StatusOK, gin.H{
 		"message":  "metadata updated successfully",
 		"metadata": metadata,
@@ -249,13 +304,26 @@ func (c *MediaController) ChangeMediaVisibility(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
 	// Update media visibility
-	err = c.MediaService.ChangeMediaVisibility(mediaID, int64(userID), requestBody.Visibility)
+	err = c.MediaService.ChangeMediaVisibility(mediaID, int64(userID), requestBody.Visibility, version)
+	if errors.Is(err, service.ErrVersionConflict) {
+		c.mediaConflict(ctx, mediaID)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to change media visibility"})
 		return
 	}
 
+	if media, err := c.MediaService.GetMediaByID(mediaID); err == nil {
+		setETag(ctx, media.Version)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"message": "media visibility changed successfully"})
 }
 
@@ -327,6 +395,7 @@ func (c *MediaController) GetMediaByID(ctx *gin.Context) {
         return
     }
 
+    setETag(ctx, media.Version)
     ctx.JSON(http.StatusOK, media)
 }
 
@@ -377,12 +446,12 @@ func (c *MediaController) DeleteMedia(ctx *gin.Context) {
 		return
 	}
 
-	// Delete the media from both MinIO and database
-	err = c.MediaService.DeleteMediaCompletely(mediaID, int64(userID))
+	// The file stays in MinIO until the trash is purged
+	err = c.MediaService.TrashMedia(mediaID, int64(userID))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete media: " + err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "media deleted successfully"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "media moved to trash"})
 }
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePageRequest reads the limit, cursor and sort query parameters.
+func parsePageRequest(ctx *gin.Context) (models.PageRequest, error) {
+	page := models.PageRequest{
+		Cursor: ctx.Query("cursor"),
+		Sort:   models.TripSort(ctx.Query("sort")),
+	}
+
+	if limit := ctx.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l <= 0 {
+			return page, fmt.Errorf("invalid limit")
+		}
+		page.Limit = l
+	}
+
+	if err := page.Normalize(); err != nil {
+		return page, err
+	}
+
+	dateRange, err := parseDateRange(ctx.Query("from"), ctx.Query("to"))
+	if err != nil {
+		return page, err
+	}
+	page.Range = dateRange
+
+	if page.Cursor != "" {
+		cursor, err := models.DecodePageCursor(page.Cursor)
+		if err != nil {
+			return page, err
+		}
+		if cursor.Sort != page.Sort {
+			return page, fmt.Errorf("cursor does not match sort %q", page.Sort)
+		}
+	}
+
+	return page, nil
+}
+
+// nextCursorHeader carries the cursor of the following page, so trip lists
+// keep returning a plain array.
+const nextCursorHeader = "X-Next-Cursor"
+
+// respondTripPage writes a page of a trip list, with the cursor of the
+// following page in the X-Next-Cursor header when there is one.
+func respondTripPage(ctx *gin.Context, trips any, nextCursor string) {
+	if nextCursor != "" {
+		ctx.Header(nextCursorHeader, nextCursor)
+	}
+	ctx.JSON(http.StatusOK, trips)
+}
+
+// parseDateRange reads an optional pair of ISO 8601 dates.
+func parseDateRange(from, to string) (models.DateRange, error) {
+	var r models.DateRange
+	if from != "" {
+		d, err := models.ParseDate(from)
+		if err != nil {
+			return r, fmt.Errorf("invalid from date: %w", err)
+		}
+		r.From = &d
+	}
+	if to != "" {
+		d, err := models.ParseDate(to)
+		if err != nil {
+			return r, fmt.Errorf("invalid to date: %w", err)
+		}
+		r.To = &d
+	}
+	if r.From != nil && r.To != nil && r.To.Before(r.From.Time) {
+		return r, fmt.Errorf("from must not be after to")
+	}
+	return r, nil
+}
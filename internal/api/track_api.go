@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *TripController) GetTripTracks(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	tracks, err := c.TrackService.GetTracks(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get tracks for trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve tracks"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"trip_id": tripID, "tracks": tracks})
+}
+
+// ImportTrackTrip creates a new trip from an uploaded GPX, TCX or FIT file.
+func (c *TripController) ImportTrackTrip(ctx *gin.Context) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	filename, data, ok := readTrackFile(ctx)
+	if !ok {
+		return
+	}
+
+	visibility := models.VisibilityEnum(ctx.Request.FormValue("visibility"))
+	trip, track, err := c.TrackService.ImportTrip(userID, filename, data, ctx.Request.FormValue("name"), visibility)
+	if err != nil {
+		fmt.Printf("Error: Failed to import trip from track %s - %v\n", filename, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"trip": trip, "track": track})
+}
+
+func (c *TripController) AddTripTrack(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	filename, data, ok := readTrackFile(ctx)
+	if !ok {
+		return
+	}
+
+	track, err := c.TrackService.AddTrack(tripID, userID, filename, data)
+	if err != nil {
+		fmt.Printf("Error: Failed to add track to trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, track)
+}
+
+func (c *TripController) DeleteTripTrack(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	trackID, err := strconv.ParseInt(ctx.Param("track_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid track ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.TrackService.DeleteTrack(tripID, trackID, userID); err != nil {
+		fmt.Printf("Error: Failed to delete track %d - %v\n", trackID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to delete track"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "track deleted successfully"})
+}
+
+// readTrackFile reads the "track" form file, responding with the error
+// itself when it is missing or too large.
+func readTrackFile(ctx *gin.Context) (string, []byte, bool) {
+	file, header, err := ctx.Request.FormFile("track")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "no track file provided"})
+		return "", nil, false
+	}
+	defer file.Close()
+
+	if header.Size > models.MaxTrackFileSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("track files are limited to %d MB", models.MaxTrackFileSize>>20)})
+		return "", nil, false
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, models.MaxTrackFileSize))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to read track file"})
+		return "", nil, false
+	}
+	return header.Filename, data, true
+}
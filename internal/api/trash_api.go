@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *TripController) GetTrash(ctx *gin.Context) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	trash, err := c.TrashService.GetTrash(userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get trash for user %d - %v\n", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve trash"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, trash)
+}
+
+func (c *TripController) RestoreTrip(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	trip, err := c.TrashService.RestoreTrip(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to restore trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to restore trip"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, trip)
+}
+
+func (c *TripController) RestoreMedia(ctx *gin.Context) {
+	mediaID, err := strconv.ParseInt(ctx.Param("media_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid media ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	media, err := c.TrashService.RestoreMedia(mediaID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to restore media %d - %v\n", mediaID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, media)
+}
@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"main/internal/models"
 	"main/internal/service"
 	"net/http"
@@ -11,24 +14,39 @@ import (
 )
 
 type TripController struct {
-	TripService      *service.TripService
-	MediaService     *service.MediaService
-	AuthClient       *service.AuthClient
-	ProfileClient    *service.ProfileClient
-	AlbumTripService *service.AlbumsTripsService
-	LikesClient      *service.LikesClient // Add this line
+	TripService       *service.TripService
+	MediaService      *service.MediaService
+	AuthClient        *service.AuthClient
+	ProfileClient     *service.ProfileClient
+	AlbumTripService  *service.AlbumsTripsService
+	LikesClient       *service.LikesClient // Add this line
+	ItineraryService  *service.ItineraryService
+	InvitationService *service.TripInvitationService
+	StatsService      *service.TripStatsService
+	TrashService      *service.TrashService
+	ExpenseService    *service.ExpenseService
+	ChecklistService  *service.ChecklistService
+	JournalService    *service.JournalService
+	CommentService    *service.CommentService
+	ExportService     *service.TripExportService
+	TrackService      *service.TrackService
+	ImportService     *service.ImportService
+	CalendarService   *service.CalendarService
 }
 
 func (c *TripController) CreateTrip(ctx *gin.Context) {
 	fmt.Printf("Starting CreateTrip request\n")
 
 	var req struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		Visibility  string `json:"visibility"`
-		StartDate   string `json:"start_date"`
-		EndDate     string `json:"end_date"`
-		AlbumID     any    `json:"album_id"`
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Visibility  string   `json:"visibility"`
+		StartDate   string   `json:"start_date"`
+		EndDate     string   `json:"end_date"`
+		Language    string   `json:"language"`
+		Category    string   `json:"category"`
+		Tags        []string `json:"tags"`
+		AlbumID     any      `json:"album_id"`
 	}
 
 	// Get user ID from authenticated context
@@ -53,12 +71,17 @@ func (c *TripController) CreateTrip(ctx *gin.Context) {
 	}
 
 	tripMapper := &models.TripMapper{}
-	trip := tripMapper.ToTripRequest(req, TokenResponse)
+	trip, err := tripMapper.ToTripRequest(req, TokenResponse)
+	if err != nil {
+		fmt.Printf("Error: Invalid trip request - %v\n", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	createdTrip, err := c.TripService.CreateTrip(trip)
 	if err != nil {
 		fmt.Printf("Error: Failed to create trip - %v\n", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create trip"})
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to create trip"})
 		return
 	}
 	trip = createdTrip.(models.Trip)
@@ -89,12 +112,15 @@ func (c *TripController) CreateTrip(ctx *gin.Context) {
 
 func (c *TripController) UpdateTrip(ctx *gin.Context) {
 	var req struct {
-		ID          int    `json:"id"`
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		Visibility  string `json:"visibility"`
-		StartDate   string `json:"start_date"`
-		EndDate     string `json:"end_date"`
+		ID          int      `json:"id"`
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Visibility  string   `json:"visibility"`
+		StartDate   string   `json:"start_date"`
+		EndDate     string   `json:"end_date"`
+		Language    string   `json:"language"`
+		Category    string   `json:"category"`
+		Tags        []string `json:"tags"`
 	}
 	// Get user ID from authenticated context
 	tokenCookie, err := ctx.Cookie("auth_token")
@@ -114,16 +140,93 @@ func (c *TripController) UpdateTrip(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
 	tripMapper := &models.TripMapper{}
-	trip := tripMapper.ToTripUpdate(req, TokenResponse)
-	result, err := c.TripService.UpdateTrip(trip)
+	trip, err := tripMapper.ToTripUpdate(req, TokenResponse)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update trip"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	_, err = c.TripService.UpdateTrip(trip, TokenResponse, version)
+	if errors.Is(err, service.ErrVersionConflict) {
+		c.tripConflict(ctx, trip.TripID, TokenResponse)
+		return
+	}
+	if err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to update trip"})
+		return
+	}
+
+	result, err := c.TripService.GetTripDetails(strconv.Itoa(trip.TripID), TokenResponse)
+	if err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve trip"})
+		return
+	}
+	setETag(ctx, result.Version)
 	ctx.JSON(http.StatusOK, gin.H{"message": "trip updated successfully", "trip": result})
 }
 
+// PatchTrip takes a JSON merge patch (RFC 7396): fields left out are kept and
+// null clears them.
+func (c *TripController) PatchTrip(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	if contentType := ctx.ContentType(); contentType != models.MergePatchContentType && contentType != gin.MIMEJSON {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "expected " + models.MergePatchContentType})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	body, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var patch models.TripPatch
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "the patch must be a JSON object"})
+		return
+	}
+
+	version, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	trip, err := c.TripService.PatchTrip(tripID, userID, patch, version)
+	if errors.Is(err, service.ErrVersionConflict) {
+		c.tripConflict(ctx, tripID, userID)
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error: Failed to patch trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	setETag(ctx, trip.Version)
+	ctx.JSON(http.StatusOK, trip)
+}
+
 func (c *TripController) DeleteTrip(ctx *gin.Context) {
 	// Get user ID from authenticated context
 	tokenCookie, err := ctx.Cookie("auth_token")
@@ -140,20 +243,28 @@ func (c *TripController) DeleteTrip(ctx *gin.Context) {
 
 	tripID := ctx.Param("id")
 
-	deleteMedia := ctx.DefaultQuery("delete_media", "false")
-	shouldDeleteMedia := deleteMedia == "true"
+	trip, err := c.TripService.GetTripByID(tripID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "trip not found"})
+		return
+	}
 
-	if shouldDeleteMedia {
-		err := c.MediaService.DeleteMediaByTripID(tripID)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete media"})
-			return
-		}
+	role, err := c.TripService.GetUserRole(trip, TokenResponse)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check trip permissions"})
+		return
 	}
+	if role != models.RoleOwner {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "only trip owners can delete a trip"})
+		return
+	}
+
+	deleteMedia := ctx.DefaultQuery("delete_media", "false")
+	shouldDeleteMedia := deleteMedia == "true"
 
-	err = c.TripService.DeleteTrip(tripID)
+	err = c.TripService.DeleteTrip(tripID, TokenResponse, shouldDeleteMedia)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete trip"})
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to delete trip"})
 		return
 	}
 	if shouldDeleteMedia {
@@ -166,7 +277,7 @@ func (c *TripController) DeleteTrip(ctx *gin.Context) {
 func (c *TripController) GetTripByID(ctx *gin.Context) {
 	tripID := ctx.Param("id")
 
-	trip, err := c.TripService.GetTripByID(tripID)
+	trip, err := c.TripService.GetTripDetails(tripID, service.AnonymousViewer)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "trip not found"})
 		return
@@ -183,12 +294,20 @@ func (c *TripController) GetTripByID(ctx *gin.Context) {
 		"media": media,
 	}
 
+	setETag(ctx, trip.Version)
 	ctx.JSON(http.StatusOK, tripWithMedia)
 }
 
 func (c *TripController) SearchTrips(ctx *gin.Context) {
 	var searchRequest struct {
-		Query string `json:"query"`
+		Query      string `json:"query" binding:"required"`
+		Language   string `json:"language"`
+		StartDate  string `json:"start_date"`
+		EndDate    string `json:"end_date"`
+		Country    string `json:"country"`
+		Visibility string `json:"visibility"`
+		OwnerID    uint   `json:"owner_id"`
+		Limit      int    `json:"limit"`
 	}
 	if err := ctx.ShouldBindJSON(&searchRequest); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -208,28 +327,42 @@ func (c *TripController) SearchTrips(ctx *gin.Context) {
 		return
 	}
 
-	trips, err := c.TripService.SearchTrips(searchRequest.Query, TokenResponse)
+	dateRange, err := parseDateRange(searchRequest.StartDate, searchRequest.EndDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := c.TripService.SearchTrips(models.TripSearchFilters{
+		Query:      searchRequest.Query,
+		Language:   searchRequest.Language,
+		Range:      dateRange,
+		Country:    searchRequest.Country,
+		Visibility: searchRequest.Visibility,
+		OwnerID:    searchRequest.OwnerID,
+		Limit:      searchRequest.Limit,
+	}, TokenResponse)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	var tripsWithMedia []gin.H
-	for _, trip := range trips {
-		media, err := c.MediaService.GetMediaByTripID(int64(trip.TripID), int64(trip.UserID))
+	for _, result := range results {
+		media, err := c.MediaService.GetMediaByTripID(int64(result.TripID), int64(TokenResponse))
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve media"})
 			return
 		}
 
-		// Skip trips that have no media
-		if len(media) == 0 {
-			continue
-		}
-
 		tripWithMedia := gin.H{
-			"trip":  trip,
+			"trip":  result.Trip,
 			"media": media,
+			"rank":  result.Rank,
+			"highlights": gin.H{
+				"name":        result.NameHighlight,
+				"description": result.DescriptionHighlight,
+			},
 		}
 		tripsWithMedia = append(tripsWithMedia, tripWithMedia)
 	}
@@ -238,13 +371,19 @@ func (c *TripController) SearchTrips(ctx *gin.Context) {
 }
 
 func (c *TripController) GetAllTrips(ctx *gin.Context) {
-	trips, err := c.TripService.GetAllTrips()
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trips, nextCursor, err := c.TripService.GetAllTrips(page)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve trips"})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, trips)
+	respondTripPage(ctx, trips, nextCursor)
 }
 
 /*
@@ -292,7 +431,13 @@ func (c *TripController) GetPublicTrips(ctx *gin.Context) {
 		return
 	}
 
-	trips, err := c.TripService.GetPublicTripsForEveryone(TokenResponse)
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trips, nextCursor, err := c.TripService.GetPublicTripsForEveryone(TokenResponse, page)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve public trips"})
 		return
@@ -306,11 +451,6 @@ func (c *TripController) GetPublicTrips(ctx *gin.Context) {
 			return
 		}
 
-		// Skip trips that have no media
-		if len(media) == 0 {
-			continue
-		}
-
 		tripWithMedia := gin.H{
 			"trip":  trip,
 			"media": media,
@@ -318,14 +458,20 @@ func (c *TripController) GetPublicTrips(ctx *gin.Context) {
 		tripsWithMedia = append(tripsWithMedia, tripWithMedia)
 	}
 
-	ctx.JSON(http.StatusOK, tripsWithMedia)
+	respondTripPage(ctx, tripsWithMedia, nextCursor)
 }
 
 func (c *TripController) GetTripsByUserID(ctx *gin.Context) {
 	userID := ctx.Param("id")
 
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Get user's trips with their associated media
-	trips, err := c.TripService.GetTripsByUserID(userID)
+	trips, nextCursor, err := c.TripService.GetTripsByUserID(userID, page)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve user's trips"})
 		return
@@ -339,11 +485,6 @@ func (c *TripController) GetTripsByUserID(ctx *gin.Context) {
 			return
 		}
 
-		// Skip trips that have no media
-		if len(media) == 0 {
-			continue
-		}
-
 		tripWithMedia := gin.H{
 			"trip":  trip,
 			"media": media,
@@ -351,7 +492,7 @@ func (c *TripController) GetTripsByUserID(ctx *gin.Context) {
 		tripsWithMedia = append(tripsWithMedia, tripWithMedia)
 	}
 
-	ctx.JSON(http.StatusOK, tripsWithMedia)
+	respondTripPage(ctx, tripsWithMedia, nextCursor)
 }
 
 func (c *TripController) GetMyTrips(ctx *gin.Context) {
@@ -374,8 +515,14 @@ func (c *TripController) GetMyTrips(ctx *gin.Context) {
 
 	fmt.Printf("Fetching trips for user ID: %d\n", TokenResponse)
 
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Get trips with their associated media
-	trips, err := c.TripService.GetMyTrips(TokenResponse)
+	trips, nextCursor, err := c.TripService.GetMyTrips(TokenResponse, page)
 	if err != nil {
 		fmt.Printf("Error: Failed to retrieve trips - %v\n", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve trips"})
@@ -403,7 +550,7 @@ func (c *TripController) GetMyTrips(ctx *gin.Context) {
 	}
 
 	fmt.Printf("Successfully completed GetMyTrips request\n")
-	ctx.JSON(http.StatusOK, tripsWithMedia)
+	respondTripPage(ctx, tripsWithMedia, nextCursor)
 }
 func (c *TripController) GetLocationsByTripID(ctx *gin.Context) {
 	fmt.Printf("Starting GetLocationsByTripID request\n")
@@ -502,52 +649,52 @@ func (c *TripController) GetFollowedUsersTrips(ctx *gin.Context) {
 	}
 	fmt.Printf("Identified %d mutual follows\n", len(mutualFollows))
 
-	var allTripsWithMedia []gin.H
+	// Mutual follows also see FRIENDS trips, everyone else only PUBLIC ones
+	var followedIDs, friendIDs []uint
 	for _, followedID := range followedUsers {
-		fmt.Printf("Processing trips for followed user ID: %d\n", followedID)
-
-		var trips []models.Trip
+		followedIDs = append(followedIDs, uint(followedID))
 		if mutualFollows[followedID] {
-			fmt.Printf("Fetching public and friends trips for mutual follow %d\n", followedID)
-			trips, err = c.TripService.GetPublicAndFriendsTripsForUser(uint(followedID))
-		} else {
-			fmt.Printf("Fetching public trips for followed user %d\n", followedID)
-			trips, err = c.TripService.GetPublicTripsForUser(uint(followedID))
+			friendIDs = append(friendIDs, uint(followedID))
 		}
+	}
 
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trips, nextCursor, err := c.TripService.GetFollowedUsersTrips(userID, followedIDs, friendIDs, page)
+	if err != nil {
+		fmt.Printf("Error: Failed to retrieve followed users' trips - %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve trips"})
+		return
+	}
+	fmt.Printf("Retrieved %d trips from followed users\n", len(trips))
+
+	var allTripsWithMedia []gin.H
+	for _, trip := range trips {
+		fmt.Printf("Processing trip ID: %d\n", trip.TripID)
+		media, err := c.MediaService.GetMediaByTripID(int64(trip.TripID), int64(userID))
 		if err != nil {
-			fmt.Printf("Error: Failed to retrieve trips for user %d - %v\n", followedID, err)
+			fmt.Printf("Error: Failed to retrieve media for trip %d - %v\n", trip.TripID, err)
 			continue
 		}
-		fmt.Printf("Retrieved %d trips for user %d\n", len(trips), followedID)
-
-		for _, trip := range trips {
-			fmt.Printf("Processing trip ID: %d\n", trip.TripID)
-			media, err := c.MediaService.GetMediaByTripID(int64(trip.TripID), int64(userID))
-			if err != nil {
-				fmt.Printf("Error: Failed to retrieve media for trip %d - %v\n", trip.TripID, err)
-				continue
-			}
-
-			if len(media) == 0 {
-				fmt.Printf("Skipping trip %d as it has no media\n", trip.TripID)
-				continue
-			}
-			fmt.Printf("Found %d media items for trip %d\n", len(media), trip.TripID)
-
-			country := "Unknown"
-			tripWithMedia := gin.H{
-				"trip":    trip,
-				"media":   media,
-				"user_id": followedID,
-				"country": country,
-			}
-			allTripsWithMedia = append(allTripsWithMedia, tripWithMedia)
+
+		fmt.Printf("Found %d media items for trip %d\n", len(media), trip.TripID)
+
+		country := "Unknown"
+		tripWithMedia := gin.H{
+			"trip":    trip,
+			"media":   media,
+			"user_id": trip.UserID,
+			"country": country,
 		}
+		allTripsWithMedia = append(allTripsWithMedia, tripWithMedia)
 	}
 
 	fmt.Printf("Successfully completed GetFollowedUsersTrips request. Returning %d trips\n", len(allTripsWithMedia))
-	ctx.JSON(http.StatusOK, allTripsWithMedia)
+	respondTripPage(ctx, allTripsWithMedia, nextCursor)
 }
 
 func (c *TripController) GetMyLikedTrips(ctx *gin.Context) {
@@ -569,19 +716,26 @@ func (c *TripController) GetMyLikedTrips(ctx *gin.Context) {
 		return
 	}
 
-	var tripsWithMedia []gin.H
-	for _, tripID := range likedTripIDs {
-		// Get trip details
-		trip, err := c.TripService.GetTripByID(fmt.Sprintf("%d", tripID))
-		if err != nil {
-			fmt.Printf("Error: Failed to get trip %d - %v\n", tripID, err)
-			continue
-		}
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		fmt.Printf("Error: Failed to get user ID - %v\n", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	trips, err := c.TripService.GetTripsByIDs(likedTripIDs, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get liked trips - %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve liked trips"})
+		return
+	}
 
+	var tripsWithMedia []gin.H
+	for _, trip := range trips {
 		// Get media for trip
 		media, err := c.MediaService.GetMediaByTripID(int64(trip.TripID), int64(trip.UserID))
 		if err != nil {
-			fmt.Printf("Error: Failed to get media for trip %d - %v\n", tripID, err)
+			fmt.Printf("Error: Failed to get media for trip %d - %v\n", trip.TripID, err)
 			continue
 		}
 
@@ -594,3 +748,110 @@ func (c *TripController) GetMyLikedTrips(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, tripsWithMedia)
 }
+
+func (c *TripController) SetTripCover(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	// A null media_id goes back to the automatic cover
+	var req struct {
+		MediaID *int64 `json:"media_id"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	trip, err := c.TripService.SetCover(tripID, userID, req.MediaID, version)
+	if errors.Is(err, service.ErrVersionConflict) {
+		c.tripConflict(ctx, tripID, userID)
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error: Failed to set cover of trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	setETag(ctx, trip.Version)
+	ctx.JSON(http.StatusOK, trip)
+}
+
+func (c *TripController) CloneTrip(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	// Every field is optional, so is the body
+	var req struct {
+		Name             string `json:"name"`
+		StartDate        string `json:"start_date"`
+		IncludeTags      bool   `json:"include_tags"`
+		IncludeItinerary bool   `json:"include_itinerary"`
+		IncludeMedia     bool   `json:"include_media"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := models.TripCloneOptions{
+		Name:             req.Name,
+		IncludeTags:      req.IncludeTags,
+		IncludeItinerary: req.IncludeItinerary,
+		IncludeMedia:     req.IncludeMedia,
+	}
+	if req.StartDate != "" {
+		startDate, err := models.ParseDate(req.StartDate)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		opts.StartDate = &startDate
+	}
+
+	trip, err := c.TripService.CloneTrip(tripID, userID, opts)
+	if err != nil {
+		fmt.Printf("Error: Failed to clone trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, trip)
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *TripController) ExportTripGPX(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	trip, gpx, err := c.ExportService.ExportGPX(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to export trip %d as GPX - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to export trip"})
+		return
+	}
+
+	sendExport(ctx, trip, "gpx", models.GPXContentType, gpx)
+}
+
+func (c *TripController) ExportTripGeoJSON(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	trip, geoJSON, err := c.ExportService.ExportGeoJSON(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to export trip %d as GeoJSON - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to export trip"})
+		return
+	}
+
+	sendExport(ctx, trip, "geojson", models.GeoJSONContentType, geoJSON)
+}
+
+func (c *TripController) ExportTripKML(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	trip, kml, err := c.ExportService.ExportKML(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to export trip %d as KML - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to export trip"})
+		return
+	}
+
+	sendExport(ctx, trip, "kml", models.KMLContentType, kml)
+}
+
+// ExportTripArchive streams the trip's original media and a manifest as a ZIP.
+// Owners may add ?include_private=true to include their own PRIVATE media.
+func (c *TripController) ExportTripArchive(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	includePrivate := ctx.DefaultQuery("include_private", "false") == "true"
+
+	manifest, err := c.ExportService.PrepareArchive(tripID, userID, includePrivate)
+	if err != nil {
+		fmt.Printf("Error: Failed to prepare the archive of trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to export trip"})
+		return
+	}
+
+	ctx.Header("Content-Type", models.ArchiveContentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, exportFilename(&manifest.Trip)))
+	ctx.Status(http.StatusOK)
+	if err := c.ExportService.WriteArchive(ctx.Writer, manifest); err != nil {
+		// The status is already sent, the client gets a truncated archive
+		fmt.Printf("Error: Failed to stream the archive of trip %d - %v\n", tripID, err)
+	}
+}
+
+// sendExport answers with the file as an attachment named after the trip.
+func sendExport(ctx *gin.Context, trip *models.Trip, extension, contentType string, data []byte) {
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, exportFilename(trip), extension))
+	ctx.Data(http.StatusOK, contentType, data)
+}
+
+// exportFilename turns the trip name into a safe file name, falling back to
+// the trip ID.
+func exportFilename(trip *models.Trip) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(trip.Name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		return fmt.Sprintf("trip-%d", trip.TripID)
+	}
+	return name
+}
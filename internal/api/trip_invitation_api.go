@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *TripController) InviteToTrip(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	var req struct {
+		UserID uint            `json:"user_id" binding:"required"`
+		Role   models.TripRole `json:"role"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invitation, err := c.InvitationService.Invite(tripID, userID, req.UserID, req.Role)
+	if err != nil {
+		fmt.Printf("Error: Failed to invite user %d to trip %d - %v\n", req.UserID, tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, invitation)
+}
+
+func (c *TripController) GetMyInvitations(ctx *gin.Context) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	invitations, err := c.InvitationService.GetMyPendingInvitations(userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get invitations for user %d - %v\n", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve invitations"})
+		return
+	}
+
+	var invitationsWithTrip []gin.H
+	for _, invitation := range invitations {
+		trip, err := c.TripService.GetTripDetails(strconv.Itoa(invitation.TripID), userID)
+		if err != nil {
+			fmt.Printf("Error: Failed to get trip %d - %v\n", invitation.TripID, err)
+			continue
+		}
+		invitationsWithTrip = append(invitationsWithTrip, gin.H{
+			"invitation": invitation,
+			"trip":       trip,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, invitationsWithTrip)
+}
+
+func (c *TripController) RespondToInvitation(ctx *gin.Context) {
+	invitationID, err := strconv.ParseInt(ctx.Param("invitation_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID"})
+		return
+	}
+
+	var req struct {
+		Status models.InvitationStatus `json:"status" binding:"required"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Status != models.InvitationAccepted && req.Status != models.InvitationDeclined {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "status must be accepted or declined"})
+		return
+	}
+
+	invitation, err := c.InvitationService.Respond(invitationID, userID, req.Status == models.InvitationAccepted)
+	if err != nil {
+		fmt.Printf("Error: Failed to respond to invitation %d - %v\n", invitationID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitation)
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *TripController) GetTripMembers(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	members, err := c.TripService.GetMembers(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to get members of trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve trip members"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+func (c *TripController) AddTripMember(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	var req struct {
+		UserID uint            `json:"user_id" binding:"required"`
+		Role   models.TripRole `json:"role" binding:"required"`
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := c.TripService.AddMember(tripID, userID, req.UserID, req.Role)
+	if err != nil {
+		fmt.Printf("Error: Failed to add member %d to trip %d - %v\n", req.UserID, tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, member)
+}
+
+func (c *TripController) RemoveTripMember(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	memberUserID, err := strconv.ParseUint(ctx.Param("user_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	if err := c.TripService.RemoveMember(tripID, userID, uint(memberUserID)); err != nil {
+		fmt.Printf("Error: Failed to remove member %d from trip %d - %v\n", memberUserID, tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to remove trip member"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "trip member removed successfully"})
+}
+
+func (c *TripController) GetSharedTrips(ctx *gin.Context) {
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	trips, err := c.TripService.GetSharedTrips(userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to retrieve shared trips - %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve shared trips"})
+		return
+	}
+
+	var tripsWithMedia []gin.H
+	for _, trip := range trips {
+		media, err := c.MediaService.GetMediaByTripID(int64(trip.TripID), int64(userID))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve media"})
+			return
+		}
+		role, err := c.TripService.GetUserRole(trip, userID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve trip role"})
+			return
+		}
+
+		tripWithMedia := gin.H{
+			"trip":  trip,
+			"media": media,
+			"role":  role,
+		}
+		tripsWithMedia = append(tripsWithMedia, tripWithMedia)
+	}
+
+	ctx.JSON(http.StatusOK, tripsWithMedia)
+}
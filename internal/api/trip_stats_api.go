@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *TripController) GetTripStats(ctx *gin.Context) {
+	tripID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid trip ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	stats, err := c.StatsService.GetTripStats(tripID, userID)
+	if err != nil {
+		fmt.Printf("Error: Failed to compute stats for trip %d - %v\n", tripID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve trip stats"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
+func (c *TripController) GetUserStats(ctx *gin.Context) {
+	targetID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || targetID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	// Friends are mutual follows, as in the following feed
+	friends := false
+	if uint(targetID) != userID {
+		followedUsers, err := c.ProfileClient.GetFollowing(tokenCookie, userID)
+		if err != nil {
+			fmt.Printf("Error: Failed to get followed users - %v\n", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve followed users"})
+			return
+		}
+		followers, err := c.ProfileClient.GetFollowers(tokenCookie, userID)
+		if err != nil {
+			fmt.Printf("Error: Failed to get followers - %v\n", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve followers"})
+			return
+		}
+		friends = containsID(followedUsers, targetID) && containsID(followers, targetID)
+	}
+
+	stats, err := c.StatsService.GetUserStats(uint(targetID), userID, friends)
+	if err != nil {
+		fmt.Printf("Error: Failed to compute stats for user %d - %v\n", targetID, err)
+		ctx.JSON(errorStatus(err), gin.H{"error": "failed to retrieve user stats"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
+func containsID(ids []int, id int) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"fmt"
+	"main/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *TripController) GetPopularTags(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(models.DefaultPageLimit)))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	tags, err := c.TripService.GetPopularTags(limit)
+	if err != nil {
+		fmt.Printf("Error: Failed to get popular tags - %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve popular tags"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tags)
+}
+
+func (c *TripController) GetTripCategories(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, models.TripCategories)
+}
+
+func (c *TripController) GetTripsByTag(ctx *gin.Context) {
+	tag := ctx.Param("tag")
+
+	tokenCookie, err := ctx.Cookie("auth_token")
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "no token found"})
+		return
+	}
+
+	userID, err := c.AuthClient.GetUserID(tokenCookie)
+	if err != nil || userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "failed to find this user"})
+		return
+	}
+
+	page, err := parsePageRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trips, nextCursor, err := c.TripService.GetPublicTripsByTag(tag, userID, page)
+	if err != nil {
+		fmt.Printf("Error: Failed to get trips tagged %q - %v\n", tag, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve trips"})
+		return
+	}
+
+	var tripsWithMedia []gin.H
+	for _, trip := range trips {
+		media, err := c.MediaService.GetMediaByTripID(int64(trip.TripID), int64(userID))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve media"})
+			return
+		}
+
+		tripWithMedia := gin.H{
+			"trip":  trip,
+			"media": media,
+		}
+		tripsWithMedia = append(tripsWithMedia, tripWithMedia)
+	}
+
+	respondTripPage(ctx, tripsWithMedia, nextCursor)
+}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"gorm.io/gorm"
+)
+
+// WithAdvisoryLock runs fn while holding the Postgres advisory lock key, so
+// that only one replica runs it at a time. The lock is held by a connection
+// set aside for it. It reports false without running fn when another session
+// already holds the lock.
+func WithAdvisoryLock(database *gorm.DB, key int64, fn func() error) (bool, error) {
+	ran := false
+	err := database.Connection(func(conn *gorm.DB) error {
+		var locked bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", key).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", key)
+
+		ran = true
+		return fn()
+	})
+	return ran, err
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"main/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CalendarTokensRepository struct {
+	DB *gorm.DB
+}
+
+func (repo *CalendarTokensRepository) CreateToken(token *models.CalendarToken) error {
+	return repo.DB.Table("trips.calendar_tokens").Create(token).Error
+}
+
+// GetTokensByUserID returns the user's tokens that are not revoked.
+func (repo *CalendarTokensRepository) GetTokensByUserID(userID uint) ([]models.CalendarToken, error) {
+	var tokens []models.CalendarToken
+	result := repo.DB.Table("trips.calendar_tokens").
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("token_id ASC").
+		Find(&tokens)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tokens, nil
+}
+
+func (repo *CalendarTokensRepository) GetTokenByID(tokenID int64) (*models.CalendarToken, error) {
+	var token models.CalendarToken
+	result := repo.DB.Table("trips.calendar_tokens").Where("token_id = ?", tokenID).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+// GetActiveTokenByHash fails with gorm.ErrRecordNotFound for unknown and
+// revoked tokens alike.
+func (repo *CalendarTokensRepository) GetActiveTokenByHash(tokenHash string) (*models.CalendarToken, error) {
+	var token models.CalendarToken
+	result := repo.DB.Table("trips.calendar_tokens").
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+func (repo *CalendarTokensRepository) TouchToken(tokenID int64, usedAt time.Time) error {
+	return repo.DB.Table("trips.calendar_tokens").Where("token_id = ?", tokenID).Update("last_used_at", usedAt).Error
+}
+
+func (repo *CalendarTokensRepository) RevokeToken(tokenID int64) error {
+	return repo.DB.Table("trips.calendar_tokens").
+		Where("token_id = ? AND revoked_at IS NULL", tokenID).
+		Update("revoked_at", time.Now()).Error
+}
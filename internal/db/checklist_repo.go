@@ -0,0 +1,228 @@
+package db
+
+import (
+	"main/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ChecklistsRepository struct {
+	DB *gorm.DB
+}
+
+func (repo *ChecklistsRepository) GetChecklistsByTripID(tripID int) ([]models.Checklist, error) {
+	var checklists []models.Checklist
+	result := repo.DB.Table("trips.checklists").
+		Where("trip_id = ?", tripID).
+		Order("position ASC, checklist_id ASC").
+		Find(&checklists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return checklists, nil
+}
+
+func (repo *ChecklistsRepository) GetChecklistByID(checklistID int64) (*models.Checklist, error) {
+	var checklist models.Checklist
+	result := repo.DB.Table("trips.checklists").Where("checklist_id = ?", checklistID).First(&checklist)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &checklist, nil
+}
+
+// CreateChecklist appends the checklist after the trip's others, together
+// with its items in order.
+func (repo *ChecklistsRepository) CreateChecklist(checklist *models.Checklist) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		var maxPosition int
+		err := tx.Table("trips.checklists").
+			Where("trip_id = ?", checklist.TripID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&maxPosition).Error
+		if err != nil {
+			return err
+		}
+		checklist.Position = maxPosition + 1
+		if err := tx.Table("trips.checklists").Create(checklist).Error; err != nil {
+			return err
+		}
+
+		for i := range checklist.Items {
+			checklist.Items[i].ChecklistID = checklist.ChecklistID
+			checklist.Items[i].TripID = checklist.TripID
+			checklist.Items[i].Position = i + 1
+		}
+		if len(checklist.Items) == 0 {
+			return nil
+		}
+		return tx.Table("trips.checklist_items").Create(&checklist.Items).Error
+	})
+}
+
+func (repo *ChecklistsRepository) DeleteChecklist(checklistID int64) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.checklist_items").Where("checklist_id = ?", checklistID).Delete(&models.ChecklistItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Table("trips.checklists").Where("checklist_id = ?", checklistID).Delete(&models.Checklist{}).Error
+	})
+}
+
+func (repo *ChecklistsRepository) GetItemsByTripID(tripID int) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+	result := repo.DB.Table("trips.checklist_items").
+		Where("trip_id = ?", tripID).
+		Order("checklist_id ASC, position ASC").
+		Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+func (repo *ChecklistsRepository) GetItemsByChecklistID(checklistID int64) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+	result := repo.DB.Table("trips.checklist_items").
+		Where("checklist_id = ?", checklistID).
+		Order("position ASC").
+		Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+func (repo *ChecklistsRepository) GetItemByID(itemID int64) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	result := repo.DB.Table("trips.checklist_items").Where("item_id = ?", itemID).First(&item)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+// CreateItem appends the item at the end of its checklist.
+func (repo *ChecklistsRepository) CreateItem(item *models.ChecklistItem) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		var maxPosition int
+		err := tx.Table("trips.checklist_items").
+			Where("checklist_id = ?", item.ChecklistID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&maxPosition).Error
+		if err != nil {
+			return err
+		}
+		item.Position = maxPosition + 1
+		return tx.Table("trips.checklist_items").Create(item).Error
+	})
+}
+
+func (repo *ChecklistsRepository) UpdateItem(itemID int64, fields map[string]any) error {
+	return repo.DB.Table("trips.checklist_items").Where("item_id = ?", itemID).Updates(fields).Error
+}
+
+func (repo *ChecklistsRepository) DeleteItem(itemID int64) error {
+	return repo.DB.Table("trips.checklist_items").Where("item_id = ?", itemID).Delete(&models.ChecklistItem{}).Error
+}
+
+// ReorderItems rewrites the positions of a checklist's items to follow itemIDs.
+func (repo *ChecklistsRepository) ReorderItems(checklistID int64, itemIDs []int64) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		for i, itemID := range itemIDs {
+			err := tx.Table("trips.checklist_items").
+				Where("item_id = ? AND checklist_id = ?", itemID, checklistID).
+				Update("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (repo *ChecklistsRepository) GetTemplatesByUserID(userID uint) ([]models.ChecklistTemplate, error) {
+	var templates []models.ChecklistTemplate
+	result := repo.DB.Table("trips.checklist_templates").
+		Where("user_id = ?", userID).
+		Order("title ASC, template_id ASC").
+		Find(&templates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(templates) == 0 {
+		return templates, nil
+	}
+
+	templateIDs := make([]int64, len(templates))
+	for i, template := range templates {
+		templateIDs[i] = template.TemplateID
+	}
+	var items []models.ChecklistTemplateItem
+	result = repo.DB.Table("trips.checklist_template_items").
+		Where("template_id IN ?", templateIDs).
+		Order("template_id ASC, position ASC").
+		Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	itemsByTemplate := make(map[int64][]string)
+	for _, item := range items {
+		itemsByTemplate[item.TemplateID] = append(itemsByTemplate[item.TemplateID], item.Text)
+	}
+	for i := range templates {
+		templates[i].Items = itemsByTemplate[templates[i].TemplateID]
+		if templates[i].Items == nil {
+			templates[i].Items = []string{}
+		}
+	}
+	return templates, nil
+}
+
+func (repo *ChecklistsRepository) GetTemplateByID(templateID int64) (*models.ChecklistTemplate, error) {
+	var template models.ChecklistTemplate
+	result := repo.DB.Table("trips.checklist_templates").Where("template_id = ?", templateID).First(&template)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var items []models.ChecklistTemplateItem
+	result = repo.DB.Table("trips.checklist_template_items").
+		Where("template_id = ?", templateID).
+		Order("position ASC").
+		Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	template.Items = make([]string, len(items))
+	for i, item := range items {
+		template.Items[i] = item.Text
+	}
+	return &template, nil
+}
+
+func (repo *ChecklistsRepository) CreateTemplate(template *models.ChecklistTemplate) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.checklist_templates").Create(template).Error; err != nil {
+			return err
+		}
+		if len(template.Items) == 0 {
+			return nil
+		}
+		items := make([]models.ChecklistTemplateItem, len(template.Items))
+		for i, text := range template.Items {
+			items[i] = models.ChecklistTemplateItem{TemplateID: template.TemplateID, Position: i + 1, Text: text}
+		}
+		return tx.Table("trips.checklist_template_items").Create(&items).Error
+	})
+}
+
+func (repo *ChecklistsRepository) DeleteTemplate(templateID int64) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.checklist_template_items").Where("template_id = ?", templateID).Delete(&models.ChecklistTemplateItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Table("trips.checklist_templates").Where("template_id = ?", templateID).Delete(&models.ChecklistTemplate{}).Error
+	})
+}
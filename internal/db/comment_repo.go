@@ -0,0 +1,79 @@
+package db
+
+import (
+	"main/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CommentsRepository struct {
+	DB *gorm.DB
+}
+
+// GetThreads returns up to limit top-level comments after afterID, oldest
+// first, and every reply in their threads. mediaID nil selects the comments
+// on the trip itself.
+func (repo *CommentsRepository) GetThreads(tripID int, mediaID *int64, afterID int64, limit int) ([]models.Comment, []models.Comment, error) {
+	query := repo.DB.Table("trips.comments").
+		Where("trip_id = ? AND parent_id IS NULL", tripID)
+	if mediaID == nil {
+		query = query.Where("media_id IS NULL")
+	} else {
+		query = query.Where("media_id = ?", *mediaID)
+	}
+	if afterID > 0 {
+		query = query.Where("comment_id > ?", afterID)
+	}
+
+	var roots []models.Comment
+	if err := query.Order("comment_id ASC").Limit(limit).Find(&roots).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(roots) == 0 {
+		return roots, nil, nil
+	}
+
+	rootIDs := make([]int64, len(roots))
+	for i, root := range roots {
+		rootIDs[i] = root.CommentID
+	}
+	var replies []models.Comment
+	result := repo.DB.Table("trips.comments").
+		Where("root_id IN ? AND parent_id IS NOT NULL", rootIDs).
+		Order("comment_id ASC").
+		Find(&replies)
+	if result.Error != nil {
+		return nil, nil, result.Error
+	}
+	return roots, replies, nil
+}
+
+func (repo *CommentsRepository) GetCommentByID(commentID int64) (*models.Comment, error) {
+	var comment models.Comment
+	result := repo.DB.Table("trips.comments").Where("comment_id = ?", commentID).First(&comment)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &comment, nil
+}
+
+// CreateComment stores the comment; a top-level comment is the root of its
+// own thread.
+func (repo *CommentsRepository) CreateComment(comment *models.Comment) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.comments").Create(comment).Error; err != nil {
+			return err
+		}
+		if comment.RootID != nil {
+			return nil
+		}
+		comment.RootID = &comment.CommentID
+		return tx.Table("trips.comments").
+			Where("comment_id = ?", comment.CommentID).
+			Update("root_id", comment.CommentID).Error
+	})
+}
+
+func (repo *CommentsRepository) UpdateComment(commentID int64, fields map[string]any) error {
+	return repo.DB.Table("trips.comments").Where("comment_id = ?", commentID).Updates(fields).Error
+}
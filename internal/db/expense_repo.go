@@ -0,0 +1,112 @@
+package db
+
+import (
+	"main/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExpensesRepository struct {
+	DB *gorm.DB
+}
+
+// CreateExpense stores the expense and who it is split among.
+func (repo *ExpensesRepository) CreateExpense(expense *models.Expense) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.trip_expenses").Create(expense).Error; err != nil {
+			return err
+		}
+		splits := make([]models.ExpenseSplit, 0, len(expense.SplitAmong))
+		for _, userID := range expense.SplitAmong {
+			splits = append(splits, models.ExpenseSplit{ExpenseID: expense.ExpenseID, UserID: userID})
+		}
+		return tx.Table("trips.trip_expense_splits").Create(&splits).Error
+	})
+}
+
+// GetExpensesByTripID returns the trip's expenses, oldest first, with their splits.
+func (repo *ExpensesRepository) GetExpensesByTripID(tripID int) ([]models.Expense, error) {
+	var expenses []models.Expense
+	result := repo.DB.Table("trips.trip_expenses").
+		Where("trip_id = ?", tripID).
+		Order("expense_date ASC, expense_id ASC").
+		Find(&expenses)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(expenses) == 0 {
+		return expenses, nil
+	}
+
+	expenseIDs := make([]int64, len(expenses))
+	for i, expense := range expenses {
+		expenseIDs[i] = expense.ExpenseID
+	}
+	var splits []models.ExpenseSplit
+	result = repo.DB.Table("trips.trip_expense_splits").
+		Where("expense_id IN ?", expenseIDs).
+		Order("user_id ASC").
+		Find(&splits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	splitsByExpense := make(map[int64][]uint)
+	for _, split := range splits {
+		splitsByExpense[split.ExpenseID] = append(splitsByExpense[split.ExpenseID], split.UserID)
+	}
+	for i := range expenses {
+		expenses[i].SplitAmong = splitsByExpense[expenses[i].ExpenseID]
+	}
+	return expenses, nil
+}
+
+func (repo *ExpensesRepository) GetExpenseByID(expenseID int64) (*models.Expense, error) {
+	var expense models.Expense
+	result := repo.DB.Table("trips.trip_expenses").Where("expense_id = ?", expenseID).First(&expense)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &expense, nil
+}
+
+func (repo *ExpensesRepository) DeleteExpense(expenseID int64) error {
+	return repo.DB.Table("trips.trip_expenses").Where("expense_id = ?", expenseID).Delete(&models.Expense{}).Error
+}
+
+func (repo *ExpensesRepository) GetBudget(tripID int) (*models.TripBudget, error) {
+	var budget models.TripBudget
+	result := repo.DB.Table("trips.trip_budgets").Where("trip_id = ?", tripID).First(&budget)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &budget, nil
+}
+
+func (repo *ExpensesRepository) SaveBudget(budget *models.TripBudget) error {
+	return repo.DB.Table("trips.trip_budgets").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "trip_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"amount", "currency"}),
+		}).
+		Create(budget).Error
+}
+
+func (repo *ExpensesRepository) GetExchangeRates() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	result := repo.DB.Table("trips.exchange_rates").Order("currency ASC").Find(&rates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rates, nil
+}
+
+func (repo *ExpensesRepository) SaveExchangeRate(rate *models.ExchangeRate) error {
+	return repo.DB.Table("trips.exchange_rates").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "currency"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_by", "updated_at"}),
+		}).
+		Create(rate).Error
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"errors"
+	"main/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ErrImportInProgress is returned when the user already has an unfinished
+// import.
+var ErrImportInProgress = errors.New("an import is already in progress")
+
+// ErrImportJobFinished is returned when a job was finished, or failed for a
+// lost lease, before its progress could be saved.
+var ErrImportJobFinished = errors.New("import job is already finished")
+
+var activeImportStatuses = []models.ImportStatus{models.ImportPending, models.ImportRunning}
+
+type ImportJobsRepository struct {
+	DB *gorm.DB
+}
+
+// CreateJob saves a new job. A unique index allows a single unfinished job per
+// user.
+func (repo *ImportJobsRepository) CreateJob(job *models.ImportJob) error {
+	err := repo.DB.Table("trips.import_jobs").Create(job).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrImportInProgress
+	}
+	return err
+}
+
+func (repo *ImportJobsRepository) GetJobByID(jobID int64) (*models.ImportJob, error) {
+	var job models.ImportJob
+	result := repo.DB.Table("trips.import_jobs").Where("job_id = ?", jobID).First(&job)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+func (repo *ImportJobsRepository) GetJobsByUserID(userID uint) ([]models.ImportJob, error) {
+	var jobs []models.ImportJob
+	result := repo.DB.Table("trips.import_jobs").
+		Where("user_id = ?", userID).
+		Order("job_id DESC").
+		Find(&jobs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return jobs, nil
+}
+
+// UpdateProgress stores the job's status, source, counters, trips and error,
+// and renews its lease. A job that is no longer pending or running is left
+// alone, so an import that lost its lease cannot undo FailStaleJobs.
+func (repo *ImportJobsRepository) UpdateProgress(job *models.ImportJob) error {
+	result := repo.DB.Table("trips.import_jobs").
+		Where("job_id = ? AND status IN ?", job.JobID, activeImportStatuses).
+		Updates(map[string]any{
+			"status":          job.Status,
+			"source":          job.Source,
+			"total_items":     job.TotalItems,
+			"processed_items": job.ProcessedItems,
+			"failed_items":    job.FailedItems,
+			"trip_ids":        job.TripIDs,
+			"error":           job.Error,
+			"finished_at":     job.FinishedAt,
+			"heartbeat_at":    time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrImportJobFinished
+	}
+	return nil
+}
+
+// Heartbeat renews the lease of a running job.
+func (repo *ImportJobsRepository) Heartbeat(jobID int64) error {
+	return repo.DB.Table("trips.import_jobs").
+		Where("job_id = ? AND status IN ?", jobID, activeImportStatuses).
+		Update("heartbeat_at", time.Now()).Error
+}
+
+// FailStaleJobs marks the imports left pending or running without a
+// heartbeat since before as failed.
+func (repo *ImportJobsRepository) FailStaleJobs(before time.Time, reason string) error {
+	return repo.DB.Table("trips.import_jobs").
+		Where("status IN ? AND heartbeat_at < ?", activeImportStatuses, before).
+		Updates(map[string]any{
+			"status":      models.ImportFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"main/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ItineraryRepository struct {
+	DB *gorm.DB
+}
+
+func (repo *ItineraryRepository) GetDaysByTripID(tripID int) ([]models.ItineraryDay, error) {
+	var days []models.ItineraryDay
+	result := repo.DB.Table("trips.itinerary_days").
+		Where("trip_id = ?", tripID).
+		Order("date ASC, day_id ASC").
+		Find(&days)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return days, nil
+}
+
+func (repo *ItineraryRepository) GetDayByID(dayID int64) (*models.ItineraryDay, error) {
+	var day models.ItineraryDay
+	result := repo.DB.Table("trips.itinerary_days").Where("day_id = ?", dayID).First(&day)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &day, nil
+}
+
+func (repo *ItineraryRepository) CreateDay(day *models.ItineraryDay) error {
+	return repo.DB.Table("trips.itinerary_days").Create(day).Error
+}
+
+func (repo *ItineraryRepository) DeleteDay(dayID int64) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.itinerary_stops").Where("day_id = ?", dayID).Delete(&models.ItineraryStop{}).Error; err != nil {
+			return err
+		}
+		return tx.Table("trips.itinerary_days").Where("day_id = ?", dayID).Delete(&models.ItineraryDay{}).Error
+	})
+}
+
+func (repo *ItineraryRepository) GetStopsByTripID(tripID int) ([]models.ItineraryStop, error) {
+	var stops []models.ItineraryStop
+	result := repo.DB.Table("trips.itinerary_stops").
+		Where("trip_id = ?", tripID).
+		Order("day_id ASC, position ASC").
+		Find(&stops)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return stops, nil
+}
+
+// GetScheduledStopsByTripIDs returns the stops of the trips that have a
+// planned start, in time order.
+func (repo *ItineraryRepository) GetScheduledStopsByTripIDs(tripIDs []int) ([]models.ItineraryStop, error) {
+	var stops []models.ItineraryStop
+	if len(tripIDs) == 0 {
+		return stops, nil
+	}
+	result := repo.DB.Table("trips.itinerary_stops").
+		Where("trip_id IN ? AND planned_start IS NOT NULL", tripIDs).
+		Order("planned_start ASC, stop_id ASC").
+		Find(&stops)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return stops, nil
+}
+
+func (repo *ItineraryRepository) GetStopsByDayID(dayID int64) ([]models.ItineraryStop, error) {
+	var stops []models.ItineraryStop
+	result := repo.DB.Table("trips.itinerary_stops").
+		Where("day_id = ?", dayID).
+		Order("position ASC").
+		Find(&stops)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return stops, nil
+}
+
+func (repo *ItineraryRepository) GetStopByID(stopID int64) (*models.ItineraryStop, error) {
+	var stop models.ItineraryStop
+	result := repo.DB.Table("trips.itinerary_stops").Where("stop_id = ?", stopID).First(&stop)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &stop, nil
+}
+
+// CreateStop appends the stop at the end of its day.
+func (repo *ItineraryRepository) CreateStop(stop *models.ItineraryStop) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		var maxPosition int
+		err := tx.Table("trips.itinerary_stops").
+			Where("day_id = ?", stop.DayID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&maxPosition).Error
+		if err != nil {
+			return err
+		}
+		stop.Position = maxPosition + 1
+		return tx.Table("trips.itinerary_stops").Create(stop).Error
+	})
+}
+
+func (repo *ItineraryRepository) DeleteStop(stopID int64) error {
+	return repo.DB.Table("trips.itinerary_stops").Where("stop_id = ?", stopID).Delete(&models.ItineraryStop{}).Error
+}
+
+// ReorderStops rewrites the positions of a day's stops to follow stopIDs.
+func (repo *ItineraryRepository) ReorderStops(dayID int64, stopIDs []int64) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		for i, stopID := range stopIDs {
+			err := tx.Table("trips.itinerary_stops").
+				Where("stop_id = ? AND day_id = ?", stopID, dayID).
+				Update("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (repo *ItineraryRepository) GetLocationsByIDs(locationIDs []int64) ([]models.Location, error) {
+	var locations []models.Location
+	if len(locationIDs) == 0 {
+		return locations, nil
+	}
+	result := repo.DB.Table("locations.locations").Where("location_id IN ?", locationIDs).Find(&locations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return locations, nil
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"main/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type JournalRepository struct {
+	DB *gorm.DB
+}
+
+func (repo *JournalRepository) GetEntriesByTripID(tripID int) ([]models.JournalEntry, error) {
+	var entries []models.JournalEntry
+	result := repo.DB.Table("trips.journal_entries").
+		Where("trip_id = ?", tripID).
+		Order("entry_date ASC, entry_id ASC").
+		Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if err := repo.loadMediaIDs(entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (repo *JournalRepository) GetEntryByID(entryID int64) (*models.JournalEntry, error) {
+	var entry models.JournalEntry
+	result := repo.DB.Table("trips.journal_entries").Where("entry_id = ?", entryID).First(&entry)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	entries := []models.JournalEntry{entry}
+	if err := repo.loadMediaIDs(entries); err != nil {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+func (repo *JournalRepository) CreateEntry(entry *models.JournalEntry) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.journal_entries").Create(entry).Error; err != nil {
+			return err
+		}
+		return saveEntryMedia(tx, entry.EntryID, entry.MediaIDs)
+	})
+}
+
+// UpdateEntry stores the entry's fields and replaces its media references.
+func (repo *JournalRepository) UpdateEntry(entry *models.JournalEntry) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("trips.journal_entries").
+			Where("entry_id = ?", entry.EntryID).
+			Updates(map[string]any{
+				"entry_date":  entry.Date,
+				"title":       entry.Title,
+				"body":        entry.Body,
+				"location_id": entry.LocationID,
+				"visibility":  entry.Visibility,
+				"updated_at":  entry.UpdatedAt,
+			}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Table("trips.journal_entry_media").Where("entry_id = ?", entry.EntryID).Delete(&models.JournalEntryMedia{}).Error; err != nil {
+			return err
+		}
+		return saveEntryMedia(tx, entry.EntryID, entry.MediaIDs)
+	})
+}
+
+func (repo *JournalRepository) DeleteEntry(entryID int64) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.journal_entry_media").Where("entry_id = ?", entryID).Delete(&models.JournalEntryMedia{}).Error; err != nil {
+			return err
+		}
+		return tx.Table("trips.journal_entries").Where("entry_id = ?", entryID).Delete(&models.JournalEntry{}).Error
+	})
+}
+
+func saveEntryMedia(tx *gorm.DB, entryID int64, mediaIDs []int64) error {
+	if len(mediaIDs) == 0 {
+		return nil
+	}
+	links := make([]models.JournalEntryMedia, len(mediaIDs))
+	for i, mediaID := range mediaIDs {
+		links[i] = models.JournalEntryMedia{EntryID: entryID, MediaID: mediaID, Position: i + 1}
+	}
+	return tx.Table("trips.journal_entry_media").Create(&links).Error
+}
+
+func (repo *JournalRepository) loadMediaIDs(entries []models.JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	entryIDs := make([]int64, len(entries))
+	for i, entry := range entries {
+		entryIDs[i] = entry.EntryID
+	}
+
+	var links []models.JournalEntryMedia
+	result := repo.DB.Table("trips.journal_entry_media").
+		Where("entry_id IN ?", entryIDs).
+		Order("entry_id ASC, position ASC").
+		Find(&links)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	mediaByEntry := make(map[int64][]int64)
+	for _, link := range links {
+		mediaByEntry[link.EntryID] = append(mediaByEntry[link.EntryID], link.MediaID)
+	}
+	for i := range entries {
+		entries[i].MediaIDs = mediaByEntry[entries[i].EntryID]
+		if entries[i].MediaIDs == nil {
+			entries[i].MediaIDs = []int64{}
+		}
+	}
+	return nil
+}
@@ -2,20 +2,25 @@ package db
 
 import (
 	"main/internal/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MediaRepository struct {
 	DB *gorm.DB
 }
 
-func (repo *MediaRepository) UpdateMedia(d int64, media *models.Media) error {
-	result := repo.DB.Table("media.media").Where("media_id = ?", d).Updates(media)
-	if result.Error != nil {
-		return result.Error
-	}
-	return nil
+// UpdateMedia writes the non-zero fields of media. A non-zero version must
+// match the stored one, or ErrVersionConflict is returned.
+func (repo *MediaRepository) UpdateMedia(d int64, media *models.Media, version int) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, "media.media", "media_id", d, version); err != nil {
+			return err
+		}
+		return tx.Table("media.media").Where("media_id = ?", d).Omit("version").Updates(media).Error
+	})
 }
 
 func (repo *MediaRepository) GetMediaByTripID(tripID int64) ([]*models.Media, error) {
@@ -27,6 +32,18 @@ func (repo *MediaRepository) GetMediaByTripID(tripID int64) ([]*models.Media, er
 	return media, nil
 }
 
+func (repo *MediaRepository) GetMediaByTripIDs(tripIDs []int) ([]*models.Media, error) {
+	var media []*models.Media
+	if len(tripIDs) == 0 {
+		return media, nil
+	}
+	result := repo.DB.Table("media.media").Where("trip_id IN ?", tripIDs).Find(&media)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return media, nil
+}
+
 func (repo *MediaRepository) SaveMedia(media *models.Media) error {
 	result := repo.DB.Table("media.media").Create(media)
 	if result.Error != nil {
@@ -36,7 +53,7 @@ func (repo *MediaRepository) SaveMedia(media *models.Media) error {
 }
 
 func (repo *MediaRepository) DeleteMediaByTripID(tripID int) error {
-	result := repo.DB.Table("media.media").Where("trip_id =?", tripID).Delete("media.media")
+	result := repo.DB.Table("media.media").Where("trip_id =?", tripID).Delete(&models.Media{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -63,13 +80,29 @@ func (repo *MediaRepository) GetMediaByID(mediaID int64) (*models.Media, error)
 
 func (r *MediaRepository) AreFriends(userID1, userID2 int64) bool {
 	var count int64
-	r.DB.Table("friendships").
-		Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)",
-			userID1, userID2, userID2, userID1).
-		Count(&count)
+	friendshipsBetween(r.DB, userID1, userID2).Count(&count)
 	return count > 0
 }
 
+// friendshipsBetween selects the friendships linking user1 and user2, either
+// of which may be a column expression such as gorm.Expr("m.user_id").
+func friendshipsBetween(tx *gorm.DB, user1, user2 any) *gorm.DB {
+	return tx.Table("friendships").
+		Where("(friendships.user_id = ? AND friendships.friend_id = ?) OR (friendships.user_id = ? AND friendships.friend_id = ?)",
+			user1, user2, user2, user1)
+}
+
+// mediaVisibleTo is the SQL form of MediaService.CanViewMedia for the media
+// table named table: PRIVATE media are only visible to their uploader and
+// FRIENDS media to the uploader's friends too. viewer may be a user ID or a
+// column expression.
+func mediaVisibleTo(tx *gorm.DB, table string, viewer any) *gorm.DB {
+	uploader := gorm.Expr(table + ".user_id")
+	return tx.Where(table+".visibility NOT IN ?", []models.VisibilityEnum{models.Private, models.Friends}).
+		Or(table+".user_id = ?", viewer).
+		Or(table+".visibility = ? AND EXISTS (?)", models.Friends, friendshipsBetween(tx, uploader, viewer).Select("1"))
+}
+
 func (r *MediaRepository) GetLocationByCountryAndCity(location *models.Location) (*models.Location, error) {
 	var result models.Location
 	dbResult := r.DB.Table("locations.locations").
@@ -102,3 +135,144 @@ func (r *MediaRepository) SaveLocationInfo(location *models.Location) error {
 	}
 	return nil
 }
+
+func (repo *MediaRepository) GetMediaByIDs(mediaIDs []int64) ([]models.Media, error) {
+	var media []models.Media
+	if len(mediaIDs) == 0 {
+		return media, nil
+	}
+	result := repo.DB.Table("media.media").Where("media_id IN ?", mediaIDs).Find(&media)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return media, nil
+}
+
+// GetAutomaticCovers picks, for each trip, the earliest geotagged photo that is
+// at least as visible as the trip itself and that viewerID may see. Ties on
+// capture date go to the lowest media ID so the pick is stable.
+func (repo *MediaRepository) GetAutomaticCovers(tripIDs []int, viewerID int64) (map[int]models.Media, error) {
+	covers := make(map[int]models.Media)
+	if len(tripIDs) == 0 {
+		return covers, nil
+	}
+
+	var media []models.Media
+	result := repo.DB.Table("media.media m").
+		Select("DISTINCT ON (m.trip_id) m.*").
+		Joins("JOIN trips.trips t ON t.trip_id = m.trip_id").
+		Where("m.trip_id IN ?", tripIDs).
+		Where("m.deleted_at IS NULL AND m.type = 'photo' AND (m.gps_latitude <> 0 OR m.gps_longitude <> 0)").
+		Where(`m.visibility = 'PUBLIC'
+			OR m.visibility = t.visibility
+			OR (m.visibility = 'FRIENDS' AND t.visibility = 'PRIVATE')`).
+		Where(mediaVisibleTo(repo.DB, "m", viewerID)).
+		Order("m.trip_id, m.capture_date, m.media_id").
+		Scan(&media)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, m := range media {
+		covers[int(m.TripID)] = m
+	}
+	return covers, nil
+}
+
+func (r *MediaRepository) GetLocationsByIDs(locationIDs []int64) ([]models.Location, error) {
+	var locations []models.Location
+	if len(locationIDs) == 0 {
+		return locations, nil
+	}
+	result := r.DB.Table("locations.locations").Where("location_id IN ?", locationIDs).Find(&locations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return locations, nil
+}
+
+func (repo *MediaRepository) GetTrashedMedia(userID int64) ([]models.Media, error) {
+	var media []models.Media
+	result := repo.DB.Unscoped().Table("media.media").
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&media)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return media, nil
+}
+
+func (repo *MediaRepository) GetTrashedMediaByID(mediaID int64) (*models.Media, error) {
+	var media models.Media
+	result := repo.DB.Unscoped().Table("media.media").Where("media_id = ? AND deleted_at IS NOT NULL", mediaID).First(&media)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &media, nil
+}
+
+func (repo *MediaRepository) RestoreMedia(mediaID int64) error {
+	return repo.DB.Table("media.media").Where("media_id = ?", mediaID).Update("deleted_at", nil).Error
+}
+
+func (repo *MediaRepository) GetMediaTrashedBefore(cutoff time.Time) ([]models.Media, error) {
+	var media []models.Media
+	result := repo.DB.Unscoped().Table("media.media").Where("deleted_at < ?", cutoff).Find(&media)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return media, nil
+}
+
+// PurgeMedia removes a trashed media row for good and returns its stored file
+// when no other row points at it any more, for the caller to delete once the
+// row is gone.
+func (repo *MediaRepository) PurgeMedia(mediaID int64) ([]string, error) {
+	var orphaned []string
+	err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the row so it cannot be restored halfway through
+		var media models.Media
+		if err := tx.Unscoped().Table("media.media").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("media_id = ? AND deleted_at IS NOT NULL", mediaID).
+			First(&media).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM media.media WHERE media_id = ?", mediaID).Error; err != nil {
+			return err
+		}
+		var err error
+		orphaned, err = unreferencedFiles(tx, []string{media.FilePath})
+		return err
+	})
+	return orphaned, err
+}
+
+// unreferencedFiles returns the files among filePaths that no media row,
+// trashed or not, points at. Cloned trips share files with their source.
+func unreferencedFiles(tx *gorm.DB, filePaths []string) ([]string, error) {
+	if len(filePaths) == 0 {
+		return nil, nil
+	}
+	var referenced []string
+	if err := tx.Unscoped().Table("media.media").
+		Where("file_path IN ?", filePaths).
+		Distinct().
+		Pluck("file_path", &referenced).Error; err != nil {
+		return nil, err
+	}
+	inUse := make(map[string]bool, len(referenced))
+	for _, path := range referenced {
+		inUse[path] = true
+	}
+
+	var orphaned []string
+	for _, path := range filePaths {
+		if !inUse[path] {
+			orphaned = append(orphaned, path)
+			inUse[path] = true
+		}
+	}
+	return orphaned, nil
+}
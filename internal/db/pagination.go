@@ -0,0 +1,116 @@
+package db
+
+import (
+	"fmt"
+	"main/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type tripSortKey struct {
+	column string
+	desc   bool
+	value  func(models.Trip) string
+}
+
+var tripSortKeys = map[models.TripSort]tripSortKey{
+	// Trip IDs are sequential, so the newest trips come first
+	models.SortCreated: {
+		desc: true,
+	},
+	// Undated trips sort last
+	models.SortStartDate: {
+		column: "COALESCE(start_date, '-infinity'::date)",
+		desc:   true,
+		value: func(t models.Trip) string {
+			if t.StartDate == nil {
+				return "-infinity"
+			}
+			return t.StartDate.String()
+		},
+	},
+	models.SortName: {
+		column: "name",
+		value:  func(t models.Trip) string { return t.Name },
+	},
+}
+
+// findTripsPage runs query ordered by the requested sort, starting after the
+// cursor, and returns the cursor for the following page if there is one.
+func findTripsPage(query *gorm.DB, page models.PageRequest) ([]models.Trip, string, error) {
+	if err := page.Normalize(); err != nil {
+		return nil, "", err
+	}
+	key := tripSortKeys[page.Sort]
+	query = whereTripOverlaps(query, page.Range)
+
+	op, dir := ">", "ASC"
+	if key.desc {
+		op, dir = "<", "DESC"
+	}
+
+	if page.Cursor != "" {
+		cursor, err := models.DecodePageCursor(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if cursor.Sort != page.Sort {
+			return nil, "", fmt.Errorf("cursor does not match sort %q", page.Sort)
+		}
+		if key.column == "" {
+			query = query.Where(fmt.Sprintf("trips.trips.trip_id %s ?", op), cursor.TripID)
+		} else {
+			query = query.Where(fmt.Sprintf("(%s, trips.trips.trip_id) %s (?, ?)", key.column, op), cursor.Value, cursor.TripID)
+		}
+	}
+
+	if key.column != "" {
+		query = query.Order(fmt.Sprintf("%s %s", key.column, dir))
+	}
+	query = query.Order(fmt.Sprintf("trips.trips.trip_id %s", dir))
+
+	// Fetch one extra row to know whether another page follows
+	var trips []models.Trip
+	if err := query.Limit(page.Limit + 1).Find(&trips).Error; err != nil {
+		return nil, "", err
+	}
+	if len(trips) <= page.Limit {
+		return trips, "", nil
+	}
+
+	trips = trips[:page.Limit]
+	last := trips[len(trips)-1]
+	next := models.PageCursor{Sort: page.Sort, TripID: last.TripID}
+	if key.value != nil {
+		next.Value = key.value(last)
+	}
+	return trips, next.Encode(), nil
+}
+
+// whereTripOverlaps keeps the trips whose dates overlap the range. A trip
+// without an end date is treated as a single day.
+func whereTripOverlaps(query *gorm.DB, r models.DateRange) *gorm.DB {
+	if r.IsZero() {
+		return query
+	}
+	query = query.Where("start_date IS NOT NULL")
+	if r.From != nil {
+		query = query.Where("COALESCE(end_date, start_date) >= ?", *r.From)
+	}
+	if r.To != nil {
+		query = query.Where("start_date <= ?", *r.To)
+	}
+	return query
+}
+
+// whereTripHasMedia keeps the trips with at least one live media item that
+// viewer may see. Feeds filter here, before the page is cut, so pages are
+// never short. viewer may be a user ID or a column expression.
+func whereTripHasMedia(query *gorm.DB, viewer any) *gorm.DB {
+	visible := query.Session(&gorm.Session{NewDB: true})
+	media := visible.Table("media.media m").
+		Select("1").
+		Where("m.trip_id = trips.trips.trip_id AND m.deleted_at IS NULL").
+		Where(mediaVisibleTo(visible, "m", viewer))
+	return query.Where("EXISTS (?)", media)
+}
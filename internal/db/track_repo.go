@@ -0,0 +1,98 @@
+package db
+
+import (
+	"errors"
+	"main/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TracksRepository struct {
+	DB *gorm.DB
+}
+
+func (repo *TracksRepository) GetTracksByTripID(tripID int) ([]models.Track, error) {
+	var tracks []models.Track
+	result := repo.DB.Table("trips.trip_tracks").
+		Where("trip_id = ?", tripID).
+		Order("started_at ASC NULLS LAST, track_id ASC").
+		Find(&tracks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tracks, nil
+}
+
+func (repo *TracksRepository) GetTrackByID(trackID int64) (*models.Track, error) {
+	var track models.Track
+	result := repo.DB.Table("trips.trip_tracks").Where("track_id = ?", trackID).First(&track)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &track, nil
+}
+
+// CreateTrack stores the track and its points in one transaction.
+func (repo *TracksRepository) CreateTrack(track *models.Track) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		return createTrack(tx, track)
+	})
+}
+
+// CreateTripWithTrack creates trip and stores track against it in one
+// transaction, so a track that fails to save leaves no trip behind.
+func (repo *TracksRepository) CreateTripWithTrack(trip *models.Trip, track *models.Track) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.trips").Create(trip).Error; err != nil {
+			return err
+		}
+		track.TripID = trip.TripID
+		return createTrack(tx, track)
+	})
+}
+
+func createTrack(tx *gorm.DB, track *models.Track) error {
+	if err := tx.Table("trips.trip_tracks").Create(track).Error; err != nil {
+		return err
+	}
+	for i := range track.Points {
+		track.Points[i].TrackID = track.TrackID
+		track.Points[i].TripID = track.TripID
+	}
+	return tx.Table("trips.trip_track_points").CreateInBatches(track.Points, 1000).Error
+}
+
+// DeleteTrack removes the track, its points go with it.
+func (repo *TracksRepository) DeleteTrack(trackID int64) error {
+	return repo.DB.Table("trips.trip_tracks").Where("track_id = ?", trackID).Delete(&models.Track{}).Error
+}
+
+// GetPointsAround returns the last timed point of the trip at or before t and
+// the first one at or after it. Either is nil when there is none.
+func (repo *TracksRepository) GetPointsAround(tripID int, t time.Time) (*models.TrackPoint, *models.TrackPoint, error) {
+	before, err := repo.firstPoint(repo.DB.Table("trips.trip_track_points").
+		Where("trip_id = ? AND time <= ?", tripID, t).
+		Order("time DESC"))
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := repo.firstPoint(repo.DB.Table("trips.trip_track_points").
+		Where("trip_id = ? AND time >= ?", tripID, t).
+		Order("time ASC"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+func (repo *TracksRepository) firstPoint(query *gorm.DB) (*models.TrackPoint, error) {
+	var point models.TrackPoint
+	if err := query.First(&point).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &point, nil
+}
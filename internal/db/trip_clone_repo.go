@@ -0,0 +1,116 @@
+package db
+
+import (
+	"main/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CloneTrip creates clone, owned by clone.UserID, with whatever opts asks to
+// copy from the source trip, and its album links when copyAlbums is set.
+// Itinerary dates are moved by dayOffset days.
+func (repo *TripsRepository) CloneTrip(sourceID int, clone *models.Trip, opts models.TripCloneOptions, dayOffset int, copyAlbums bool) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.trips").Create(clone).Error; err != nil {
+			return err
+		}
+
+		if opts.IncludeTags {
+			if err := tx.Exec(`INSERT INTO trips.trip_tags (trip_id, tag)
+				SELECT ?, tag FROM trips.trip_tags WHERE trip_id = ?`, clone.TripID, sourceID).Error; err != nil {
+				return err
+			}
+		}
+
+		if copyAlbums {
+			if err := tx.Exec(`INSERT INTO albums.album_trips (album_id, trip_id)
+				SELECT album_id, ? FROM albums.album_trips WHERE trip_id = ? AND deleted_at IS NULL`, clone.TripID, sourceID).Error; err != nil {
+				return err
+			}
+		}
+
+		if opts.IncludeItinerary {
+			if err := cloneItinerary(tx, sourceID, clone.TripID, dayOffset); err != nil {
+				return err
+			}
+		}
+
+		if !opts.IncludeMedia {
+			return nil
+		}
+		return cloneMedia(tx, sourceID, clone)
+	})
+}
+
+// cloneMedia references the source media the clone's owner can see from the
+// clone. The rows are locked so their visibility cannot change while they are
+// copied. The copies keep their uploader and visibility, so the uploader stays
+// in control of who sees them.
+func cloneMedia(tx *gorm.DB, sourceID int, clone *models.Trip) error {
+	var media []models.Media
+	result := tx.Table("media.media").
+		Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("trip_id = ?", sourceID).
+		Where(mediaVisibleTo(tx, "media.media", int64(clone.UserID))).
+		Order("media_id").
+		Find(&media)
+	if result.Error != nil {
+		return result.Error
+	}
+	if len(media) == 0 {
+		return nil
+	}
+
+	for i := range media {
+		media[i].MediaID = 0
+		media[i].Version = 0
+		media[i].TripID = int64(clone.TripID)
+	}
+	return tx.Table("media.media").Create(&media).Error
+}
+
+func cloneItinerary(tx *gorm.DB, sourceID int, cloneID int, dayOffset int) error {
+	var days []models.ItineraryDay
+	if err := tx.Table("trips.itinerary_days").Where("trip_id = ?", sourceID).Order("date ASC, day_id ASC").Find(&days).Error; err != nil {
+		return err
+	}
+	if len(days) == 0 {
+		return nil
+	}
+
+	var stops []models.ItineraryStop
+	if err := tx.Table("trips.itinerary_stops").Where("trip_id = ?", sourceID).Order("day_id ASC, position ASC").Find(&stops).Error; err != nil {
+		return err
+	}
+
+	newDayIDs := make(map[int64]int64)
+	for _, day := range days {
+		oldDayID := day.DayID
+		day.DayID = 0
+		day.TripID = cloneID
+		day.Date = models.NewDate(day.Date.AddDate(0, 0, dayOffset))
+		if err := tx.Table("trips.itinerary_days").Create(&day).Error; err != nil {
+			return err
+		}
+		newDayIDs[oldDayID] = day.DayID
+	}
+
+	for _, stop := range stops {
+		stop.StopID = 0
+		stop.TripID = cloneID
+		stop.DayID = newDayIDs[stop.DayID]
+		if stop.PlannedStart != nil {
+			shifted := stop.PlannedStart.AddDate(0, 0, dayOffset)
+			stop.PlannedStart = &shifted
+		}
+		if stop.PlannedEnd != nil {
+			shifted := stop.PlannedEnd.AddDate(0, 0, dayOffset)
+			stop.PlannedEnd = &shifted
+		}
+		if err := tx.Table("trips.itinerary_stops").Create(&stop).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"errors"
+	"main/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvitationNotPending is returned when an invitation was answered or
+// expired in the meantime.
+var ErrInvitationNotPending = errors.New("invitation is no longer pending")
+
+type TripInvitationsRepository struct {
+	DB *gorm.DB
+}
+
+func (repo *TripInvitationsRepository) CreateInvitation(invitation *models.TripInvitation) error {
+	return repo.DB.Table("trips.trip_invitations").Create(invitation).Error
+}
+
+func (repo *TripInvitationsRepository) GetInvitationByID(invitationID int64) (*models.TripInvitation, error) {
+	var invitation models.TripInvitation
+	result := repo.DB.Table("trips.trip_invitations").Where("invitation_id = ?", invitationID).First(&invitation)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invitation, nil
+}
+
+func (repo *TripInvitationsRepository) GetPendingInvitation(tripID int, inviteeID uint) (*models.TripInvitation, error) {
+	var invitation models.TripInvitation
+	result := repo.DB.Table("trips.trip_invitations").
+		Where("trip_id = ? AND invitee_id = ? AND status = ? AND expires_at > ?",
+			tripID, inviteeID, models.InvitationPending, time.Now()).
+		First(&invitation)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invitation, nil
+}
+
+func (repo *TripInvitationsRepository) GetPendingInvitationsForUser(inviteeID uint) ([]models.TripInvitation, error) {
+	var invitations []models.TripInvitation
+	result := repo.DB.Table("trips.trip_invitations").
+		Where("invitee_id = ? AND status = ? AND expires_at > ?", inviteeID, models.InvitationPending, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return invitations, nil
+}
+
+// Respond moves a pending, unexpired invitation to status and, when member is
+// set, adds the member in the same transaction unless they already belong to
+// the trip. Concurrent answers fail with ErrInvitationNotPending.
+func (repo *TripInvitationsRepository) Respond(invitationID int64, status models.InvitationStatus, respondedAt time.Time, member *models.TripMember) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("trips.trip_invitations").
+			Where("invitation_id = ? AND status = ? AND expires_at > ?", invitationID, models.InvitationPending, respondedAt).
+			Updates(map[string]any{"status": status, "responded_at": respondedAt})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvitationNotPending
+		}
+		if member == nil {
+			return nil
+		}
+		return addMember(tx, member)
+	})
+}
+
+// ExpireInvitation flags the invitation as expired if it is still pending.
+func (repo *TripInvitationsRepository) ExpireInvitation(invitationID int64) error {
+	return repo.DB.Table("trips.trip_invitations").
+		Where("invitation_id = ? AND status = ?", invitationID, models.InvitationPending).
+		Update("status", models.InvitationExpired).Error
+}
+
+// ExpireInvitations flags every pending invitation whose deadline has passed.
+func (repo *TripInvitationsRepository) ExpireInvitations() error {
+	return repo.DB.Table("trips.trip_invitations").
+		Where("status = ? AND expires_at <= ?", models.InvitationPending, time.Now()).
+		Update("status", models.InvitationExpired).Error
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"main/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TripMembersRepository struct {
+	DB *gorm.DB
+}
+
+func (repo *TripMembersRepository) GetMember(tripID int, userID uint) (*models.TripMember, error) {
+	var member models.TripMember
+	result := repo.DB.Table("trips.trip_members").
+		Where("trip_id = ? AND user_id = ?", tripID, userID).
+		First(&member)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &member, nil
+}
+
+func (repo *TripMembersRepository) GetMembersByTripID(tripID int) ([]models.TripMember, error) {
+	var members []models.TripMember
+	result := repo.DB.Table("trips.trip_members").
+		Where("trip_id = ?", tripID).
+		Order("added_at ASC").
+		Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return members, nil
+}
+
+// SaveMember inserts the member or updates the role of an existing one.
+func (repo *TripMembersRepository) SaveMember(member *models.TripMember) error {
+	return repo.DB.Table("trips.trip_members").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "trip_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role"}),
+		}).
+		Create(member).Error
+}
+
+// addMember inserts the member and leaves the role of an existing one alone,
+// so a stale invitation never overrides a role granted since.
+func addMember(tx *gorm.DB, member *models.TripMember) error {
+	return tx.Table("trips.trip_members").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "trip_id"}, {Name: "user_id"}},
+			DoNothing: true,
+		}).
+		Create(member).Error
+}
+
+func (repo *TripMembersRepository) DeleteMember(tripID int, userID uint) error {
+	return repo.DB.Table("trips.trip_members").
+		Where("trip_id = ? AND user_id = ?", tripID, userID).
+		Delete(&models.TripMember{}).Error
+}
+
+func (repo *TripMembersRepository) GetTripsByMember(userID uint) ([]models.Trip, error) {
+	var trips []models.Trip
+	result := repo.DB.Table("trips.trips").
+		Joins("JOIN trips.trip_members tm ON tm.trip_id = trips.trips.trip_id").
+		Where("tm.user_id = ?", userID).
+		Find(&trips)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return trips, nil
+}
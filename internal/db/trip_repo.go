@@ -3,75 +3,296 @@ package db
 import (
 	"fmt"
 	"main/internal/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TripsRepository struct {
 	DB *gorm.DB
 }
 
+// CreateTrip inserts the trip together with its tags.
 func (repo *TripsRepository) CreateTrip(trip models.Trip) (any, error) {
-	result := repo.DB.Table("trips.trips").Create(&trip)
-	if result.Error != nil {
-		return nil, result.Error
+	err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("trips.trips").Create(&trip).Error; err != nil {
+			return err
+		}
+		if len(trip.Tags) == 0 {
+			return nil
+		}
+		return setTags(tx, trip.TripID, trip.Tags)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return trip, nil
 }
 
-func (repo *TripsRepository) UpdateTrip(trip models.Trip) (any, error) {
-	result := repo.DB.Table("trips.trips").Where("trip_id = ?", trip.TripID).Updates(&trip)
-	if result.Error != nil {
-		return nil, result.Error
+// UpdateTrip writes the non-zero fields of trip, and replaces its tags when
+// trip.Tags is not nil. A non-zero version must match the stored one, or
+// ErrVersionConflict is returned. What changed is recorded as revision,
+// unless it is nil.
+func (repo *TripsRepository) UpdateTrip(trip models.Trip, version int, revision *models.TripRevision) (any, error) {
+	err := repo.updateWithRevision(trip.TripID, version, revision, func(tx *gorm.DB) error {
+		if err := tx.Table("trips.trips").Where("trip_id = ?", trip.TripID).Omit("version").Updates(&trip).Error; err != nil {
+			return err
+		}
+		if trip.Tags == nil {
+			return nil
+		}
+		return setTags(tx, trip.TripID, trip.Tags)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return trip, nil
 }
 
-func (repo *TripsRepository) DeleteTrip(tripID int) error {
-	result := repo.DB.Table("albums.album_trips").Where("trip_id =?", tripID).Delete("trips.trips")
-	if result.Error != nil {
-		return result.Error
-	}
+// UpdateTripFields writes the given columns as they are, zero values and
+// NULLs included, and replaces the tags when tags is not nil. A non-zero
+// version must match the stored one. What changed is recorded as revision,
+// unless it is nil.
+func (repo *TripsRepository) UpdateTripFields(tripID int, fields map[string]any, tags []string, version int, revision *models.TripRevision) error {
+	return repo.updateWithRevision(tripID, version, revision, func(tx *gorm.DB) error {
+		if len(fields) > 0 {
+			if err := tx.Table("trips.trips").Where("trip_id = ? AND deleted_at IS NULL", tripID).Updates(fields).Error; err != nil {
+				return err
+			}
+		}
+		if tags == nil {
+			return nil
+		}
+		return setTags(tx, tripID, tags)
+	})
+}
 
-	result = repo.DB.Table("trips.trips").Delete(&models.Trip{}, tripID)
-	if result.Error != nil {
-		return result.Error
+// updateWithRevision locks the trip's row, applies update and records the
+// difference as revision, all in one transaction.
+func (repo *TripsRepository) updateWithRevision(tripID int, version int, revision *models.TripRevision, update func(tx *gorm.DB) error) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := lockTripWithTags(tx, tripID)
+		if err != nil {
+			return err
+		}
+		if err := bumpVersion(tx, "trips.trips", "trip_id", tripID, version); err != nil {
+			return err
+		}
+		if err := update(tx); err != nil {
+			return err
+		}
+		if revision == nil {
+			return nil
+		}
+
+		after, err := lockTripWithTags(tx, tripID)
+		if err != nil {
+			return err
+		}
+		changes := models.DiffTrips(before, after)
+		if len(changes) == 0 {
+			return nil
+		}
+		revision.TripID = tripID
+		revision.Changes = changes
+		return createRevision(tx, revision)
+	})
+}
+
+// lockTripWithTags reads the trip and its tags, locking the trip's row until
+// the transaction ends.
+func lockTripWithTags(tx *gorm.DB, tripID int) (models.Trip, error) {
+	var trip models.Trip
+	if err := tx.Table("trips.trips").Clauses(clause.Locking{Strength: "UPDATE"}).First(&trip, tripID).Error; err != nil {
+		return models.Trip{}, err
 	}
-	return nil
+	tags, err := getTags(tx, tripID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	trip.Tags = tags
+	return trip, nil
+}
+
+// SetCoverMedia sets the trip's cover, or clears it when mediaID is nil. A
+// non-zero version must match the stored one.
+func (repo *TripsRepository) SetCoverMedia(tripID int, mediaID *int64, version int) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, "trips.trips", "trip_id", tripID, version); err != nil {
+			return err
+		}
+		return tx.Table("trips.trips").Where("trip_id = ?", tripID).Update("cover_media_id", mediaID).Error
+	})
+}
+
+// DeleteTrip moves the trip and its album links to the trash, along with its
+// media when withMedia is set. Everything trashed together shares the same
+// deleted_at so RestoreTrip can bring it back as a whole.
+func (repo *TripsRepository) DeleteTrip(tripID int, withMedia bool) error {
+	now := time.Now()
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("albums.album_trips").Where("trip_id = ? AND deleted_at IS NULL", tripID).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		if withMedia {
+			if err := tx.Table("media.media").Where("trip_id = ? AND deleted_at IS NULL", tripID).Update("deleted_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Table("trips.trips").Where("trip_id = ? AND deleted_at IS NULL", tripID).Update("deleted_at", now).Error
+	})
 }
 
-func (repo *TripsRepository) GetAllTrips() ([]models.Trip, error) {
+func (repo *TripsRepository) GetTrashedTrips(userID uint) ([]models.Trip, error) {
 	var trips []models.Trip
-	result := repo.DB.Table("trips.trips").Find(&trips)
+	result := repo.DB.Unscoped().Table("trips.trips").
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&trips)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return trips, nil
 }
 
-func (repo *TripsRepository) GetMyTrips(userID uint) ([]models.Trip, error) {
+func (repo *TripsRepository) GetTrashedTripByID(tripID int) (models.Trip, error) {
+	var trip models.Trip
+	result := repo.DB.Unscoped().Table("trips.trips").Where("trip_id = ? AND deleted_at IS NOT NULL", tripID).First(&trip)
+	if result.Error != nil {
+		return models.Trip{}, result.Error
+	}
+	return trip, nil
+}
+
+// RestoreTrip takes the trip out of the trash with whatever was trashed
+// together with it.
+func (repo *TripsRepository) RestoreTrip(tripID int, deletedAt time.Time) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("albums.album_trips").Where("trip_id = ? AND deleted_at = ?", tripID, deletedAt).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Table("media.media").Where("trip_id = ? AND deleted_at = ?", tripID, deletedAt).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Table("trips.trips").Where("trip_id = ?", tripID).Update("deleted_at", nil).Error
+	})
+}
+
+func (repo *TripsRepository) GetTripsTrashedBefore(cutoff time.Time) ([]models.Trip, error) {
 	var trips []models.Trip
-	result := repo.DB.Table("trips.trips").Where("user_id = ?", userID).Find(&trips)
+	result := repo.DB.Unscoped().Table("trips.trips").Where("deleted_at < ?", cutoff).Find(&trips)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return trips, nil
 }
 
-func (repo *TripsRepository) SearchTrips(query string, userID uint) ([]models.Trip, error) {
-	var trips []models.Trip
-	searchQuery := "%" + query + "%"
-	result := repo.DB.Table("trips.trips").
-		Where("user_id != ? AND (name ILIKE ? OR description ILIKE ?)",
-			userID, searchQuery, searchQuery).
-		Find(&trips)
+// PurgeTrip removes a trashed trip for good, together with all of its media,
+// trashed or not. Tags, itinerary, members and invitations go with it through
+// their foreign keys. It returns the stored files no other row points at any
+// more, for the caller to delete once the rows are gone.
+func (repo *TripsRepository) PurgeTrip(tripID int) ([]string, error) {
+	var orphaned []string
+	err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the trip so it cannot be restored halfway through
+		var trip models.Trip
+		if err := tx.Unscoped().Table("trips.trips").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("trip_id = ? AND deleted_at IS NOT NULL", tripID).
+			First(&trip).Error; err != nil {
+			return err
+		}
+		var filePaths []string
+		if err := tx.Raw("DELETE FROM media.media WHERE trip_id = ? RETURNING file_path", tripID).
+			Scan(&filePaths).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM albums.album_trips WHERE trip_id = ?", tripID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM trips.trips WHERE trip_id = ?", tripID).Error; err != nil {
+			return err
+		}
+		var err error
+		orphaned, err = unreferencedFiles(tx, filePaths)
+		return err
+	})
+	return orphaned, err
+}
+
+func (repo *TripsRepository) GetAllTrips(page models.PageRequest) ([]models.Trip, string, error) {
+	return findTripsPage(repo.DB.Table("trips.trips"), page)
+}
+
+func (repo *TripsRepository) GetMyTrips(userID uint, page models.PageRequest) ([]models.Trip, string, error) {
+	return findTripsPage(repo.DB.Table("trips.trips").Where("user_id = ?", userID), page)
+}
+
+// SearchTrips ranks the trips visible to userID against the full-text query.
+// Without an explicit language each trip is matched with its own stemming,
+// and the unstemmed 'simple' query catches place names and exact words.
+func (repo *TripsRepository) SearchTrips(filters models.TripSearchFilters, userID uint) ([]models.TripSearchResult, error) {
+	language := "trips.trips.language"
+	var languageArgs []any
+	if filters.Language != "" {
+		language = "?::regconfig"
+		languageArgs = []any{filters.Language}
+	}
+	tsQuery := fmt.Sprintf("(websearch_to_tsquery(%s, ?) || websearch_to_tsquery('simple', ?))", language)
+	tsQueryArgs := append(append([]any{}, languageArgs...), filters.Query, filters.Query)
+
+	headline := func(column string) string {
+		return fmt.Sprintf("ts_headline(%s, COALESCE(%s, ''), %s, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, HighlightAll=%t')",
+			language, column, tsQuery, column == "name")
+	}
+	var selectArgs []any
+	selectArgs = append(selectArgs, tsQueryArgs...)
+	selectArgs = append(selectArgs, languageArgs...)
+	selectArgs = append(selectArgs, tsQueryArgs...)
+	selectArgs = append(selectArgs, languageArgs...)
+	selectArgs = append(selectArgs, tsQueryArgs...)
+
+	query := repo.DB.Table("trips.trips").
+		Where("trips.trips.deleted_at IS NULL").
+		Select(fmt.Sprintf("trips.trips.*, ts_rank_cd(search_vector, %s) AS rank, %s AS name_highlight, %s AS description_highlight",
+			tsQuery, headline("name"), headline("description")), selectArgs...).
+		Where(fmt.Sprintf("search_vector @@ %s", tsQuery), tsQueryArgs...)
+
+	// Only trips the user is allowed to see
+	query = query.Where(repo.DB.
+		Where("visibility = ?", "PUBLIC").
+		Or("user_id = ?", userID).
+		Or("visibility = ? AND EXISTS (?)", "FRIENDS", friendshipsBetween(repo.DB, gorm.Expr("trips.trips.user_id"), userID).Select("1")).
+		Or("EXISTS (SELECT 1 FROM trips.trip_members tm WHERE tm.trip_id = trips.trips.trip_id AND tm.user_id = ?)", userID))
+
+	// The user's own trips only show up when asking for them explicitly
+	if filters.OwnerID != 0 {
+		query = query.Where("user_id = ?", filters.OwnerID)
+	} else {
+		query = query.Where("user_id != ?", userID)
+	}
+	if filters.Visibility != "" {
+		query = query.Where("visibility = ?", filters.Visibility)
+	}
+	query = whereTripOverlaps(query, filters.Range)
+	if filters.Country != "" {
+		media := repo.DB.Table("media.media m").
+			Select("1").
+			Joins("JOIN locations.locations l ON l.location_id = m.location_id").
+			Where("m.trip_id = trips.trips.trip_id AND m.deleted_at IS NULL AND l.country ILIKE ?", filters.Country).
+			Where(mediaVisibleTo(repo.DB, "m", userID))
+		query = query.Where("EXISTS (?)", media)
+	}
+
+	var results []models.TripSearchResult
+	result := query.Order("rank DESC").Order("trips.trips.trip_id DESC").Limit(filters.Limit).Scan(&results)
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	fmt.Printf("Search results from DB: %+v\n", trips)
-	return trips, nil
+	return results, nil
 }
 
 func (repo *TripsRepository) GetTripByID(tripID int) (models.Trip, error) {
@@ -84,29 +305,46 @@ func (repo *TripsRepository) GetTripByID(tripID int) (models.Trip, error) {
 	return trip, nil
 }
 
-func (r *TripsRepository) GetAllPublicTrips() ([]models.Trip, error) {
+// GetTripsByIDs returns the trips among tripIDs that still exist, in no
+// particular order.
+func (repo *TripsRepository) GetTripsByIDs(tripIDs []uint) ([]models.Trip, error) {
 	var trips []models.Trip
-	result := r.DB.Table("trips.trips").Where("visibility = ?", "PUBLIC").Find(&trips)
+	if len(tripIDs) == 0 {
+		return trips, nil
+	}
+	result := repo.DB.Table("trips.trips").Where("trip_id IN ?", tripIDs).Find(&trips)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return trips, nil
 }
 
-func (repo *TripsRepository) GetTripsByUserID(userID uint) ([]models.Trip, error) {
+func (r *TripsRepository) GetAllPublicTrips() ([]models.Trip, error) {
 	var trips []models.Trip
-	result := repo.DB.Table("trips.trips").Where("user_id =?", userID).Find(&trips)
-
+	result := r.DB.Table("trips.trips").Where("visibility = ?", "PUBLIC").Find(&trips)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return trips, nil
 }
 
-func (repo *TripsRepository) GetPublicTripsForEveryone(userID uint) ([]models.Trip, error) {
+// GetTripsByUserID lists the user's trips that have media the user can see.
+func (repo *TripsRepository) GetTripsByUserID(userID uint, page models.PageRequest) ([]models.Trip, string, error) {
+	return findTripsPage(whereTripHasMedia(repo.DB.Table("trips.trips").Where("user_id =?", userID), userID), page)
+}
+
+// GetPublicTripsForEveryone lists other users' PUBLIC trips with media their
+// owner can see, as the public feed shows them.
+func (repo *TripsRepository) GetPublicTripsForEveryone(userID uint, page models.PageRequest) ([]models.Trip, string, error) {
+	query := repo.DB.Table("trips.trips").
+		Where("user_id != ? AND visibility = ?", userID, "PUBLIC")
+	return findTripsPage(whereTripHasMedia(query, gorm.Expr("trips.trips.user_id")), page)
+}
+
+func (repo *TripsRepository) GetPublicTripsForUser(userID uint) ([]models.Trip, error) {
 	var trips []models.Trip
 	result := repo.DB.Table("trips.trips").
-		Where("user_id != ? AND visibility = ?", userID, "PUBLIC").
+		Where("user_id = ? AND visibility = ?", userID, "PUBLIC").
 		Find(&trips)
 
 	if result.Error != nil {
@@ -115,10 +353,10 @@ func (repo *TripsRepository) GetPublicTripsForEveryone(userID uint) ([]models.Tr
 	return trips, nil
 }
 
-func (repo *TripsRepository) GetPublicTripsForUser(userID uint) ([]models.Trip, error) {
+func (repo *TripsRepository) GetPublicAndFriendsTripsForUser(userID uint) ([]models.Trip, error) {
 	var trips []models.Trip
 	result := repo.DB.Table("trips.trips").
-		Where("user_id = ? AND visibility = ?", userID, "PUBLIC").
+		Where("user_id != ? AND (visibility = ? OR visibility = ?)", userID, "PUBLIC", "FRIENDS").
 		Find(&trips)
 
 	if result.Error != nil {
@@ -127,12 +365,28 @@ func (repo *TripsRepository) GetPublicTripsForUser(userID uint) ([]models.Trip,
 	return trips, nil
 }
 
-func (repo *TripsRepository) GetPublicAndFriendsTripsForUser(userID uint) ([]models.Trip, error) {
+// GetFollowedUsersTrips returns the PUBLIC trips of followedIDs plus the
+// FRIENDS trips of the ones in friendIDs, who follow the viewer back, that
+// have media the viewer can see.
+func (repo *TripsRepository) GetFollowedUsersTrips(viewerID uint, followedIDs []uint, friendIDs []uint, page models.PageRequest) ([]models.Trip, string, error) {
+	if len(followedIDs) == 0 {
+		return []models.Trip{}, "", nil
+	}
+	visible := repo.DB.Where("user_id IN ? AND visibility = ?", followedIDs, "PUBLIC")
+	if len(friendIDs) > 0 {
+		visible = visible.Or("user_id IN ? AND visibility = ?", friendIDs, "FRIENDS")
+	}
+	return findTripsPage(whereTripHasMedia(repo.DB.Table("trips.trips").Where(visible), viewerID), page)
+}
+
+// GetUserTripsWithVisibility returns all of the user's trips with one of the
+// given visibilities, oldest first.
+func (repo *TripsRepository) GetUserTripsWithVisibility(userID uint, visibilities []string) ([]models.Trip, error) {
 	var trips []models.Trip
 	result := repo.DB.Table("trips.trips").
-		Where("user_id != ? AND (visibility = ? OR visibility = ?)", userID, "PUBLIC", "FRIENDS").
+		Where("user_id = ? AND visibility IN ?", userID, visibilities).
+		Order("trip_id").
 		Find(&trips)
-
 	if result.Error != nil {
 		return nil, result.Error
 	}
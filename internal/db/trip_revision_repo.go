@@ -0,0 +1,49 @@
+package db
+
+import (
+	"main/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type TripRevisionsRepository struct {
+	DB *gorm.DB
+}
+
+// createRevision numbers the revision after the trip's latest one. The caller
+// holds the lock on the trip's row, so concurrent updates cannot pick the same
+// number.
+func createRevision(tx *gorm.DB, revision *models.TripRevision) error {
+	var latest int
+	if err := tx.Table("trips.trip_revisions").
+		Where("trip_id = ?", revision.TripID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+	revision.Revision = latest + 1
+	return tx.Table("trips.trip_revisions").Create(revision).Error
+}
+
+func (repo *TripRevisionsRepository) GetRevisionsByTripID(tripID int) ([]models.TripRevision, error) {
+	var revisions []models.TripRevision
+	result := repo.DB.Table("trips.trip_revisions").
+		Where("trip_id = ?", tripID).
+		Order("revision DESC").
+		Find(&revisions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return revisions, nil
+}
+
+func (repo *TripRevisionsRepository) GetRevision(tripID int, revision int) (*models.TripRevision, error) {
+	var found models.TripRevision
+	result := repo.DB.Table("trips.trip_revisions").
+		Where("trip_id = ? AND revision = ?", tripID, revision).
+		First(&found)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &found, nil
+}
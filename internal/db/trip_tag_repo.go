@@ -0,0 +1,89 @@
+package db
+
+import (
+	"main/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type TripTagsRepository struct {
+	DB *gorm.DB
+}
+
+type tripTag struct {
+	TripID int    `gorm:"column:trip_id"`
+	Tag    string `gorm:"column:tag"`
+}
+
+// SetTags replaces every tag of the trip.
+func (repo *TripTagsRepository) SetTags(tripID int, tags []string) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		return setTags(tx, tripID, tags)
+	})
+}
+
+func setTags(tx *gorm.DB, tripID int, tags []string) error {
+	if err := tx.Table("trips.trip_tags").Where("trip_id = ?", tripID).Delete(&tripTag{}).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	rows := make([]tripTag, 0, len(tags))
+	for _, tag := range tags {
+		rows = append(rows, tripTag{TripID: tripID, Tag: tag})
+	}
+	return tx.Table("trips.trip_tags").Create(&rows).Error
+}
+
+func getTags(tx *gorm.DB, tripID int) ([]string, error) {
+	tags := []string{}
+	result := tx.Table("trips.trip_tags").Where("trip_id = ?", tripID).Order("tag ASC").Pluck("tag", &tags)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+// GetTagsByTripIDs returns the tags of each trip, keyed by trip ID.
+func (repo *TripTagsRepository) GetTagsByTripIDs(tripIDs []int) (map[int][]string, error) {
+	tags := make(map[int][]string)
+	if len(tripIDs) == 0 {
+		return tags, nil
+	}
+	var rows []tripTag
+	result := repo.DB.Table("trips.trip_tags").Where("trip_id IN ?", tripIDs).Order("tag ASC").Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		tags[row.TripID] = append(tags[row.TripID], row.Tag)
+	}
+	return tags, nil
+}
+
+// GetPopularTags counts tags over PUBLIC trips.
+func (repo *TripTagsRepository) GetPopularTags(limit int) ([]models.TagCount, error) {
+	var counts []models.TagCount
+	result := repo.DB.Table("trips.trip_tags tt").
+		Select("tt.tag, COUNT(*) AS count").
+		Joins("JOIN trips.trips t ON t.trip_id = tt.trip_id").
+		Where("t.visibility = ? AND t.deleted_at IS NULL", "PUBLIC").
+		Group("tt.tag").
+		Order("count DESC, tt.tag ASC").
+		Limit(limit).
+		Scan(&counts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return counts, nil
+}
+
+// GetPublicTripsByTag follows the visibility rules of GetPublicTripsForEveryone
+// and only keeps trips with media userID can see.
+func (repo *TripTagsRepository) GetPublicTripsByTag(tag string, userID uint, page models.PageRequest) ([]models.Trip, string, error) {
+	query := repo.DB.Table("trips.trips").
+		Where("user_id != ? AND visibility = ?", userID, "PUBLIC").
+		Where("EXISTS (SELECT 1 FROM trips.trip_tags tt WHERE tt.trip_id = trips.trips.trip_id AND tt.tag = ?)", tag)
+	return findTripsPage(whereTripHasMedia(query, userID), page)
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict is returned when a row changed after the version the
+// caller based its update on.
+var ErrVersionConflict = errors.New("version conflict")
+
+// bumpVersion increments the version of a row that is not in the trash. When
+// expected is not zero the row must still be at that version.
+func bumpVersion(tx *gorm.DB, table string, idColumn string, id any, expected int) error {
+	query := tx.Table(table).Where(idColumn+" = ? AND deleted_at IS NULL", id)
+	if expected != 0 {
+		query = query.Where("version = ?", expected)
+	}
+	result := query.Update("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := tx.Table(table).Where(idColumn+" = ? AND deleted_at IS NULL", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -27,3 +27,34 @@ type MediaUploadedEvent struct {
 	Type       string    `json:"type"` // foto, video...
 	UploadedAt time.Time `json:"uploadedAt"`
 }
+
+type TripInvitationCreatedEvent struct {
+	InvitationID int64     `json:"invitationId"`
+	TripID       int       `json:"tripId"`
+	InviterID    uint      `json:"inviterId"`
+	InviteeID    uint      `json:"inviteeId"`
+	Role         string    `json:"role"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type TripInvitationAcceptedEvent struct {
+	InvitationID int64     `json:"invitationId"`
+	TripID       int       `json:"tripId"`
+	InviterID    uint      `json:"inviterId"`
+	InviteeID    uint      `json:"inviteeId"`
+	AcceptedAt   time.Time `json:"acceptedAt"`
+}
+
+// CommentCreatedEvent names who to notify: the trip's owner and, for
+// replies, the author of the comment replied to.
+type CommentCreatedEvent struct {
+	CommentID      int64     `json:"commentId"`
+	TripID         int       `json:"tripId"`
+	MediaID        *int64    `json:"mediaId,omitempty"`
+	ParentID       *int64    `json:"parentId,omitempty"`
+	AuthorID       uint      `json:"authorId"`
+	TripOwnerID    uint      `json:"tripOwnerId"`
+	ParentAuthorID *uint     `json:"parentAuthorId,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const ICalendarContentType = "text/calendar; charset=utf-8"
+
+// CalendarToken grants read access to a user's calendar feed until revoked.
+type CalendarToken struct {
+	TokenID    int64      `json:"token_id" gorm:"column:token_id;primaryKey;autoIncrement"`
+	UserID     uint       `json:"user_id" gorm:"column:user_id"`
+	TokenHash  string     `json:"-" gorm:"column:token_hash"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"column:created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" gorm:"column:revoked_at"`
+}
+
+// Calendar is an iCalendar (RFC 5545) feed.
+type Calendar struct {
+	Name        string
+	GeneratedAt time.Time
+	Events      []CalendarEvent
+}
+
+// CalendarEvent is a VEVENT. All-day events only use the dates of Start and
+// End, End being the day after the last one as iCalendar wants.
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         *time.Time
+	AllDay      bool
+}
+
+const (
+	icalDateLayout     = "20060102"
+	icalDateTimeLayout = "20060102T150405Z"
+)
+
+func (c Calendar) Encode() []byte {
+	var b bytes.Buffer
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//Nostos//Trips//EN")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText(c.Name))
+	// How often subscribed calendars should be refreshed
+	writeICalLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeICalLine(&b, "X-PUBLISHED-TTL:PT1H")
+
+	stamp := c.GeneratedAt.UTC().Format(icalDateTimeLayout)
+	for _, event := range c.Events {
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, "UID:"+event.UID)
+		writeICalLine(&b, "DTSTAMP:"+stamp)
+		if event.AllDay {
+			writeICalLine(&b, "DTSTART;VALUE=DATE:"+event.Start.Format(icalDateLayout))
+			if event.End != nil {
+				writeICalLine(&b, "DTEND;VALUE=DATE:"+event.End.Format(icalDateLayout))
+			}
+		} else {
+			writeICalLine(&b, "DTSTART:"+event.Start.UTC().Format(icalDateTimeLayout))
+			if event.End != nil {
+				writeICalLine(&b, "DTEND:"+event.End.UTC().Format(icalDateTimeLayout))
+			}
+		}
+		writeICalLine(&b, "SUMMARY:"+escapeICalText(event.Summary))
+		if event.Description != "" {
+			writeICalLine(&b, "DESCRIPTION:"+escapeICalText(event.Description))
+		}
+		if event.Location != "" {
+			writeICalLine(&b, "LOCATION:"+escapeICalText(event.Location))
+		}
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.Bytes()
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeICalText(text string) string {
+	return icalTextEscaper.Replace(text)
+}
+
+// writeICalLine folds lines longer than 75 octets, without splitting UTF-8
+// characters, and ends them with CRLF.
+func writeICalLine(b *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
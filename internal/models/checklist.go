@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+type Checklist struct {
+	ChecklistID int64     `json:"checklist_id" gorm:"column:checklist_id;primaryKey;autoIncrement"`
+	TripID      int       `json:"trip_id" gorm:"column:trip_id"`
+	Title       string    `json:"title" gorm:"column:title"`
+	Position    int       `json:"position" gorm:"column:position"`
+	CreatedBy   uint      `json:"created_by" gorm:"column:created_by"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+
+	Items []ChecklistItem `json:"items" gorm:"-"`
+}
+
+type ChecklistItem struct {
+	ItemID      int64      `json:"item_id" gorm:"column:item_id;primaryKey;autoIncrement"`
+	ChecklistID int64      `json:"checklist_id" gorm:"column:checklist_id"`
+	TripID      int        `json:"trip_id" gorm:"column:trip_id"`
+	Position    int        `json:"position" gorm:"column:position"`
+	Text        string     `json:"text" gorm:"column:text"`
+	Checked     bool       `json:"checked" gorm:"column:checked"`
+	AssigneeID  *uint      `json:"assignee_id,omitempty" gorm:"column:assignee_id"`
+	CheckedBy   *uint      `json:"checked_by,omitempty" gorm:"column:checked_by"`
+	CheckedAt   *time.Time `json:"checked_at,omitempty" gorm:"column:checked_at"`
+}
+
+// ChecklistItemUpdate holds the fields of an item to change; nil fields are
+// left as they are.
+type ChecklistItemUpdate struct {
+	Text     *string `json:"text"`
+	Checked  *bool   `json:"checked"`
+	Assignee *uint   `json:"assignee_id"`
+	// Unassign clears the assignee, as a null assignee_id cannot be told
+	// apart from a missing one
+	Unassign bool `json:"unassign"`
+}
+
+// ChecklistTemplate is a user's reusable checklist. Applying it to a trip
+// copies its items into a new checklist.
+type ChecklistTemplate struct {
+	TemplateID int64     `json:"template_id" gorm:"column:template_id;primaryKey;autoIncrement"`
+	UserID     uint      `json:"user_id" gorm:"column:user_id"`
+	Title      string    `json:"title" gorm:"column:title"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+
+	Items []string `json:"items" gorm:"-"`
+}
+
+type ChecklistTemplateItem struct {
+	TemplateID int64  `gorm:"column:template_id;primaryKey"`
+	Position   int    `gorm:"column:position;primaryKey"`
+	Text       string `gorm:"column:text"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MaxCommentLength caps a comment's body, in characters.
+const MaxCommentLength = 2000
+
+// Comment is left on a trip, or on one of its media when MediaID is set.
+// Replies point to the comment they answer with ParentID.
+type Comment struct {
+	CommentID int64      `json:"comment_id" gorm:"column:comment_id;primaryKey;autoIncrement"`
+	TripID    int        `json:"trip_id" gorm:"column:trip_id"`
+	MediaID   *int64     `json:"media_id,omitempty" gorm:"column:media_id"`
+	ParentID  *int64     `json:"parent_id,omitempty" gorm:"column:parent_id"`
+	RootID    *int64     `json:"-" gorm:"column:root_id"`
+	AuthorID  uint       `json:"author_id" gorm:"column:author_id"`
+	Body      string     `json:"body" gorm:"column:body"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty" gorm:"column:edited_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" gorm:"column:deleted_at"`
+	DeletedBy *uint      `json:"-" gorm:"column:deleted_by"`
+
+	Replies []Comment `json:"replies" gorm:"-"`
+}
+
+func (c Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// CommentPage is one page of top-level comments with their replies. Replies
+// are not paginated.
+type CommentPage struct {
+	Comments   []Comment `json:"comments"`
+	NextCursor string    `json:"next_cursor"`
+}
+
+// CommentCursor is the last top-level comment of a page.
+type CommentCursor struct {
+	CommentID int64 `json:"id"`
+}
+
+func (c CommentCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCommentCursor(cursor string) (*CommentCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var c CommentCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &c, nil
+}
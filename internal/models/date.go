@@ -0,0 +1,98 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+// Date is a calendar day without time of day, stored in DATE columns and
+// encoded in JSON as YYYY-MM-DD.
+type Date struct {
+	time.Time
+}
+
+// ParseDate accepts an ISO 8601 calendar date, or a full RFC 3339 timestamp
+// whose date part is kept.
+func ParseDate(value string) (Date, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(DateLayout, value); err == nil {
+		return Date{t}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return NewDate(t), nil
+	}
+	return Date{}, fmt.Errorf("%q is not an ISO 8601 date (YYYY-MM-DD)", value)
+}
+
+func NewDate(t time.Time) Date {
+	return Date{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+func (d *Date) UnmarshalJSON(b []byte) error {
+	value := strings.Trim(string(b), `"`)
+	if value == "null" || value == "" {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d *Date) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Date{}
+	case time.Time:
+		*d = NewDate(v)
+	case string:
+		parsed, err := ParseDate(v)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+	case []byte:
+		parsed, err := ParseDate(string(v))
+		if err != nil {
+			return err
+		}
+		*d = parsed
+	default:
+		return fmt.Errorf("cannot scan %T into Date", value)
+	}
+	return nil
+}
+
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (Date) GormDataType() string {
+	return "date"
+}
+
+// DateRange selects trips whose dates overlap [From, To]. Either bound may be
+// nil to leave that side open.
+type DateRange struct {
+	From *Date
+	To   *Date
+}
+
+func (r DateRange) IsZero() bool {
+	return r.From == nil && r.To == nil
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// BaseCurrency is the currency exchange rates are expressed against.
+const BaseCurrency = "EUR"
+
+var ExpenseCategories = []string{
+	"accommodation",
+	"transport",
+	"food",
+	"activities",
+	"shopping",
+	"fees",
+	"other",
+}
+
+func IsExpenseCategory(category string) bool {
+	for _, c := range ExpenseCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeCurrency upper-cases an ISO 4217 code and checks its shape.
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("%q is not an ISO 4217 currency code", currency)
+	}
+	return currency, nil
+}
+
+type Expense struct {
+	ExpenseID   int64     `json:"expense_id" gorm:"column:expense_id;primaryKey;autoIncrement"`
+	TripID      int       `json:"trip_id" gorm:"column:trip_id"`
+	Amount      Money     `json:"amount" gorm:"column:amount"`
+	Currency    string    `json:"currency" gorm:"column:currency"`
+	Category    string    `json:"category" gorm:"column:category"`
+	Description string    `json:"description,omitempty" gorm:"column:description"`
+	Date        Date      `json:"date" gorm:"column:expense_date"`
+	PaidBy      uint      `json:"paid_by" gorm:"column:paid_by"`
+	CreatedBy   uint      `json:"created_by" gorm:"column:created_by"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+
+	SplitAmong []uint `json:"split_among" gorm:"-"`
+}
+
+type ExpenseSplit struct {
+	ExpenseID int64 `gorm:"column:expense_id;primaryKey"`
+	UserID    uint  `gorm:"column:user_id;primaryKey"`
+}
+
+type ExchangeRate struct {
+	Currency  string    `json:"currency" gorm:"column:currency;primaryKey"`
+	Rate      float64   `json:"rate" gorm:"column:rate"`
+	UpdatedBy uint      `json:"updated_by" gorm:"column:updated_by"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// ExchangeRates maps currencies to how many units one BaseCurrency buys.
+type ExchangeRates map[string]float64
+
+// Convert moves an amount between currencies through the base currency.
+func (r ExchangeRates) Convert(amount Money, from, to string) (Money, error) {
+	if from == to {
+		return amount, nil
+	}
+	fromRate, ok := r.rate(from)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %s", from)
+	}
+	toRate, ok := r.rate(to)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %s", to)
+	}
+	return Money(math.Round(float64(amount) * toRate / fromRate)), nil
+}
+
+// Has reports whether amounts in currency can be converted.
+func (r ExchangeRates) Has(currency string) bool {
+	_, ok := r.rate(currency)
+	return ok
+}
+
+func (r ExchangeRates) rate(currency string) (float64, bool) {
+	if currency == BaseCurrency {
+		return 1, true
+	}
+	rate, ok := r[currency]
+	return rate, ok
+}
+
+type TripBudget struct {
+	TripID   int    `json:"trip_id" gorm:"column:trip_id;primaryKey"`
+	Amount   Money  `json:"amount" gorm:"column:amount"`
+	Currency string `json:"currency" gorm:"column:currency"`
+}
+
+// ExpenseSummary reports a trip's spending in a single currency.
+type ExpenseSummary struct {
+	TripID     int              `json:"trip_id"`
+	Currency   string           `json:"currency"`
+	Total      Money            `json:"total"`
+	Budget     *Money           `json:"budget,omitempty"`
+	Remaining  *Money           `json:"remaining,omitempty"`
+	ByCategory map[string]Money `json:"by_category"`
+	ByDay      []DailySpend     `json:"by_day"`
+	Balances   []Balance        `json:"balances"`
+	SettleUp   []Settlement     `json:"settle_up"`
+}
+
+type DailySpend struct {
+	Date  Date  `json:"date"`
+	Total Money `json:"total"`
+}
+
+// Balance is what a participant paid minus their share; positive means they
+// are owed money.
+type Balance struct {
+	UserID  uint  `json:"user_id"`
+	Paid    Money `json:"paid"`
+	Share   Money `json:"share"`
+	Balance Money `json:"balance"`
+}
+
+type Settlement struct {
+	From   uint  `json:"from"`
+	To     uint  `json:"to"`
+	Amount Money `json:"amount"`
+}
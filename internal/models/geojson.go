@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+const GeoJSONContentType = "application/geo+json"
+
+// GeoJSON types, see RFC 7946. Positions are [longitude, latitude] with the
+// altitude as an optional third element.
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+type GeoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   GeoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+type GeoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// NewGeoJSON builds a LineString feature for the route, when it has at
+// least two points, followed by a Point feature per media item.
+func NewGeoJSON(route TripRoute) GeoJSONFeatureCollection {
+	collection := GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []GeoJSONFeature{}}
+
+	if len(route.Points) >= 2 {
+		line := make([][]float64, len(route.Points))
+		for i, point := range route.Points {
+			line[i] = []float64{point.Longitude, point.Latitude}
+		}
+		collection.Features = append(collection.Features, GeoJSONFeature{
+			Type:     "Feature",
+			Geometry: GeoJSONGeometry{Type: "LineString", Coordinates: line},
+			Properties: map[string]any{
+				"kind":    "route",
+				"trip_id": route.Trip.TripID,
+				"name":    route.Trip.Name,
+			},
+		})
+	}
+
+	for _, point := range route.Points {
+		position := []float64{point.Longitude, point.Latitude}
+		if point.Altitude != 0 {
+			position = append(position, point.Altitude)
+		}
+		var captureDate *string
+		if !point.Time.IsZero() {
+			formatted := point.Time.UTC().Format(time.RFC3339)
+			captureDate = &formatted
+		}
+		collection.Features = append(collection.Features, GeoJSONFeature{
+			Type:     "Feature",
+			Geometry: GeoJSONGeometry{Type: "Point", Coordinates: position},
+			Properties: map[string]any{
+				"kind":         "media",
+				"media_id":     point.MediaID,
+				"type":         point.Type,
+				"name":         point.Name,
+				"capture_date": captureDate,
+				"city":         point.City,
+				"country":      point.Country,
+				"url":          point.URL,
+			},
+		})
+	}
+
+	return collection
+}
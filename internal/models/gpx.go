@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const GPXContentType = "application/gpx+xml"
+
+// GPX is a GPX 1.1 document, see https://www.topografix.com/GPX/1/1/.
+type GPX struct {
+	XMLName   xml.Name      `xml:"gpx"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Xmlns     string        `xml:"xmlns,attr"`
+	Metadata  GPXMetadata   `xml:"metadata"`
+	Waypoints []GPXWaypoint `xml:"wpt"`
+	Tracks    []GPXTrack    `xml:"trk"`
+}
+
+type GPXMetadata struct {
+	Name string    `xml:"name"`
+	Desc string    `xml:"desc,omitempty"`
+	Time time.Time `xml:"time"`
+}
+
+type GPXWaypoint struct {
+	Lat  float64    `xml:"lat,attr"`
+	Lon  float64    `xml:"lon,attr"`
+	Ele  *float64   `xml:"ele,omitempty"`
+	Time *time.Time `xml:"time,omitempty"`
+	Name string     `xml:"name,omitempty"`
+	Link *GPXLink   `xml:"link,omitempty"`
+	Type string     `xml:"type,omitempty"`
+}
+
+type GPXLink struct {
+	Href string `xml:"href,attr"`
+	Text string `xml:"text,omitempty"`
+	Type string `xml:"type,omitempty"`
+}
+
+type GPXTrack struct {
+	Name     string            `xml:"name,omitempty"`
+	Segments []GPXTrackSegment `xml:"trkseg"`
+}
+
+type GPXTrackSegment struct {
+	Points []GPXWaypoint `xml:"trkpt"`
+}
+
+// NewGPX builds a track through every point of the route, and a waypoint
+// with a link to the file for each photo.
+func NewGPX(route TripRoute, generatedAt time.Time) GPX {
+	gpx := GPX{
+		Version: "1.1",
+		Creator: "Nostos",
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Metadata: GPXMetadata{
+			Name: route.Trip.Name,
+			Desc: route.Trip.Description,
+			Time: generatedAt.UTC(),
+		},
+		Waypoints: []GPXWaypoint{},
+	}
+
+	segment := GPXTrackSegment{Points: []GPXWaypoint{}}
+	for _, point := range route.Points {
+		segment.Points = append(segment.Points, gpxPoint(point))
+
+		if point.IsPhoto() {
+			waypoint := gpxPoint(point)
+			waypoint.Name = point.Name
+			waypoint.Type = point.Type
+			if point.URL != "" {
+				waypoint.Link = &GPXLink{Href: point.URL, Text: point.Name, Type: "image"}
+			}
+			gpx.Waypoints = append(gpx.Waypoints, waypoint)
+		}
+	}
+	if len(segment.Points) > 0 {
+		gpx.Tracks = []GPXTrack{{Name: route.Trip.Name, Segments: []GPXTrackSegment{segment}}}
+	}
+
+	return gpx
+}
+
+// Encode renders the document with its XML declaration.
+func (g GPX) Encode() ([]byte, error) {
+	body, err := xml.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// gpxPoint leaves out the elevation and time when they are unknown.
+func gpxPoint(point RoutePoint) GPXWaypoint {
+	p := GPXWaypoint{Lat: point.Latitude, Lon: point.Longitude}
+	if point.Altitude != 0 {
+		altitude := point.Altitude
+		p.Ele = &altitude
+	}
+	if !point.Time.IsZero() {
+		t := point.Time.UTC()
+		p.Time = &t
+	}
+	return p
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// MaxImportArchiveSize caps uploaded import archives, in bytes.
+const MaxImportArchiveSize = 10 << 30
+
+type ImportSource string
+
+const (
+	// ImportNostos is an archive from GET /api/trips/:id/archive.zip
+	ImportNostos ImportSource = "nostos"
+	// ImportTakeout is a Google Takeout export of Google Photos
+	ImportTakeout ImportSource = "takeout"
+)
+
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "pending"
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed"
+)
+
+// ImportJob tracks the progress of an archive import. Items are the media
+// files of the archive; the ones that failed are skipped.
+type ImportJob struct {
+	JobID          int64        `json:"job_id" gorm:"column:job_id;primaryKey;autoIncrement"`
+	UserID         uint         `json:"user_id" gorm:"column:user_id"`
+	Source         ImportSource `json:"source" gorm:"column:source"`
+	Status         ImportStatus `json:"status" gorm:"column:status"`
+	TotalItems     int          `json:"total_items" gorm:"column:total_items"`
+	ProcessedItems int          `json:"processed_items" gorm:"column:processed_items"`
+	FailedItems    int          `json:"failed_items" gorm:"column:failed_items"`
+	TripIDs        TripIDList   `json:"trip_ids" gorm:"column:trip_ids;type:jsonb"`
+	Error          string       `json:"error,omitempty" gorm:"column:error"`
+	CreatedAt      time.Time    `json:"created_at" gorm:"column:created_at"`
+	FinishedAt     *time.Time   `json:"finished_at,omitempty" gorm:"column:finished_at"`
+}
+
+type TripIDList []int
+
+func (l TripIDList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *TripIDList) Scan(value any) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case nil:
+		*l = nil
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into TripIDList", value)
+}
+
+// TakeoutSidecar is the JSON file Google Takeout writes next to each photo.
+type TakeoutSidecar struct {
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	PhotoTakenTime *struct {
+		Timestamp string `json:"timestamp"`
+	} `json:"photoTakenTime"`
+	GeoData     *TakeoutGeoData `json:"geoData"`
+	GeoDataExif *TakeoutGeoData `json:"geoDataExif"`
+}
+
+type TakeoutGeoData struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude"`
+}
+
+// TakeoutAlbum is the metadata.json of a Google Photos album folder.
+type TakeoutAlbum struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// TakenAt is when the photo was taken, if the sidecar says.
+func (s TakeoutSidecar) TakenAt() (time.Time, bool) {
+	if s.PhotoTakenTime == nil {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(s.PhotoTakenTime.Timestamp, 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0).UTC(), true
+}
+
+// Position prefers the location edited in Google Photos over the EXIF one.
+// Takeout writes 0,0 when there is none.
+func (s TakeoutSidecar) Position() (TakeoutGeoData, bool) {
+	for _, geo := range []*TakeoutGeoData{s.GeoData, s.GeoDataExif} {
+		if geo != nil && (geo.Latitude != 0 || geo.Longitude != 0) {
+			return *geo, true
+		}
+	}
+	return TakeoutGeoData{}, false
+}
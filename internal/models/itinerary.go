@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type ItineraryDay struct {
+	DayID  int64  `json:"day_id" gorm:"column:day_id;primaryKey;autoIncrement"`
+	TripID int    `json:"trip_id" gorm:"column:trip_id"`
+	Date   Date   `json:"date" gorm:"column:date"`
+	Title  string `json:"title,omitempty" gorm:"column:title"`
+	Notes  string `json:"notes,omitempty" gorm:"column:notes"`
+
+	Stops []ItineraryStop `json:"stops" gorm:"-"`
+}
+
+type ItineraryStop struct {
+	StopID       int64      `json:"stop_id" gorm:"column:stop_id;primaryKey;autoIncrement"`
+	DayID        int64      `json:"day_id" gorm:"column:day_id"`
+	TripID       int        `json:"trip_id" gorm:"column:trip_id"`
+	Position     int        `json:"position" gorm:"column:position"`
+	Name         string     `json:"name" gorm:"column:name"`
+	LocationID   *int64     `json:"location_id,omitempty" gorm:"column:location_id"`
+	PlannedStart *time.Time `json:"planned_start,omitempty" gorm:"column:planned_start"`
+	PlannedEnd   *time.Time `json:"planned_end,omitempty" gorm:"column:planned_end"`
+	Notes        string     `json:"notes,omitempty" gorm:"column:notes"`
+
+	Location *Location `json:"location,omitempty" gorm:"-"`
+}
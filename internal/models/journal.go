@@ -0,0 +1,95 @@
+package models
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// MaxJournalBodyLength caps an entry's Markdown body, in bytes.
+const MaxJournalBodyLength = 100000
+
+type JournalEntry struct {
+	EntryID    int64          `json:"entry_id" gorm:"column:entry_id;primaryKey;autoIncrement"`
+	TripID     int            `json:"trip_id" gorm:"column:trip_id"`
+	AuthorID   uint           `json:"author_id" gorm:"column:author_id"`
+	Date       Date           `json:"date" gorm:"column:entry_date"`
+	Title      string         `json:"title,omitempty" gorm:"column:title"`
+	Body       string         `json:"body" gorm:"column:body"`
+	LocationID *int64         `json:"location_id,omitempty" gorm:"column:location_id"`
+	Visibility VisibilityEnum `json:"visibility" gorm:"column:visibility"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"column:updated_at"`
+
+	// BodyHTML is Body rendered by RenderMarkdown, for display
+	BodyHTML string `json:"body_html" gorm:"-"`
+	// MediaIDs are the media referenced by the body, in order of appearance
+	MediaIDs []int64       `json:"media_ids" gorm:"-"`
+	Media    []MediaByTrip `json:"media" gorm:"-"`
+	Location *Location     `json:"location,omitempty" gorm:"-"`
+}
+
+type JournalEntryMedia struct {
+	EntryID  int64 `gorm:"column:entry_id;primaryKey"`
+	MediaID  int64 `gorm:"column:media_id;primaryKey"`
+	Position int   `gorm:"column:position"`
+}
+
+var (
+	markdown = goldmark.New()
+	// journalPolicy allow-lists the HTML the Markdown renderer may produce
+	journalPolicy = newJournalPolicy()
+)
+
+func newJournalPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowURLSchemes("http", "https", "mailto", "media")
+	policy.RequireNoFollowOnLinks(true)
+	return policy
+}
+
+// RenderMarkdown renders an entry's Markdown body to HTML that is safe to
+// display. Raw HTML is dropped by the renderer, and its output is filtered
+// against an allow-list, so only http, https, mailto and media links remain.
+// Media stay as media:<id> for clients to resolve against the entry's media.
+func RenderMarkdown(body string) (string, error) {
+	var rendered bytes.Buffer
+	if err := markdown.Convert([]byte(body), &rendered); err != nil {
+		return "", err
+	}
+	return journalPolicy.Sanitize(rendered.String()), nil
+}
+
+// MediaReferences returns the media shown inline with ![caption](media:<id>),
+// without duplicates. Images inside code are not references.
+func MediaReferences(body string) []int64 {
+	source := []byte(body)
+	document := markdown.Parser().Parse(text.NewReader(source))
+
+	seen := make(map[int64]bool)
+	mediaIDs := []int64{}
+	_ = ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		image, ok := node.(*ast.Image)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		id, found := strings.CutPrefix(string(image.Destination), "media:")
+		if !found {
+			return ast.WalkContinue, nil
+		}
+		mediaID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || seen[mediaID] {
+			return ast.WalkContinue, nil
+		}
+		seen[mediaID] = true
+		mediaIDs = append(mediaIDs, mediaID)
+		return ast.WalkContinue, nil
+	})
+	return mediaIDs
+}
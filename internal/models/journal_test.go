@@ -0,0 +1,168 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownRemovesScripts(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		forbidden []string
+	}{
+		{
+			name:      "javascript link",
+			body:      "[x](javascript:alert(1))",
+			forbidden: []string{"javascript"},
+		},
+		{
+			name:      "entity encoded scheme in inline link",
+			body:      "[x](javascript&colon;alert(1))",
+			forbidden: []string{"javascript", "alert"},
+		},
+		{
+			name:      "numeric entity in reference link",
+			body:      "[x][y]\n\n[y]: javascript&#58;alert(1)",
+			forbidden: []string{"javascript", "alert"},
+		},
+		{
+			name:      "backtick in fence info string",
+			body:      "``` a`b\n<img src=x onerror=alert(1)>",
+			forbidden: []string{"<img", "onerror"},
+		},
+		{
+			name:      "indented fence after a paragraph",
+			body:      "text\n    ```\n<img src=x onerror=alert(1)>",
+			forbidden: []string{"<img", "onerror"},
+		},
+		{
+			name:      "raw script",
+			body:      "<script>alert(1)</script>",
+			forbidden: []string{"<script"},
+		},
+		{
+			name:      "html inside a paragraph",
+			body:      "hello <a href=\"javascript:alert(1)\" onclick=\"alert(1)\">there</a>",
+			forbidden: []string{"javascript", "onclick"},
+		},
+		{
+			name:      "data URI image",
+			body:      "![x](data:text/html;base64,PHNjcmlwdD4=)",
+			forbidden: []string{"data:"},
+		},
+		{
+			name:      "autolink with script scheme",
+			body:      "<javascript:alert(1)>",
+			forbidden: []string{"href=\"javascript"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := RenderMarkdown(tt.body)
+			if err != nil {
+				t.Fatalf("RenderMarkdown() error = %v", err)
+			}
+			for _, forbidden := range tt.forbidden {
+				if strings.Contains(strings.ToLower(html), forbidden) {
+					t.Errorf("RenderMarkdown(%q) = %q, contains %q", tt.body, html, forbidden)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownKeepsSafeContent(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "https link",
+			body: "[site](https://example.com/a?b=c)",
+			want: `href="https://example.com/a?b=c"`,
+		},
+		{
+			name: "mailto link",
+			body: "[mail](mailto:me@example.com)",
+			want: `href="mailto:me@example.com"`,
+		},
+		{
+			name: "relative link",
+			body: "[day two](#day-2)",
+			want: `href="#day-2"`,
+		},
+		{
+			name: "media image",
+			body: "![beach](media:42)",
+			want: `src="media:42"`,
+		},
+		{
+			name: "emphasis",
+			body: "a *great* day",
+			want: "<em>great</em>",
+		},
+		{
+			name: "code block keeps its text escaped",
+			body: "```\n<img src=x>\n```",
+			want: "&lt;img src=x&gt;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := RenderMarkdown(tt.body)
+			if err != nil {
+				t.Fatalf("RenderMarkdown() error = %v", err)
+			}
+			if !strings.Contains(html, tt.want) {
+				t.Errorf("RenderMarkdown(%q) = %q, want it to contain %q", tt.body, html, tt.want)
+			}
+		})
+	}
+}
+
+func TestMediaReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []int64
+	}{
+		{
+			name: "none",
+			body: "just words",
+			want: []int64{},
+		},
+		{
+			name: "in order without duplicates",
+			body: "![a](media:3) text ![b](media:1)\n\n![again](media:3)",
+			want: []int64{3, 1},
+		},
+		{
+			name: "links and other images are not references",
+			body: "[a](media:7) ![b](https://example.com/b.jpg)",
+			want: []int64{},
+		},
+		{
+			name: "images in code are not references",
+			body: "`![a](media:1)`\n\n```\n![b](media:2)\n```",
+			want: []int64{},
+		},
+		{
+			name: "invalid IDs are skipped",
+			body: "![a](media:abc) ![b](media:5)",
+			want: []int64{5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MediaReferences(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MediaReferences(%q) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const KMLContentType = "application/vnd.google-earth.kml+xml"
+
+// KML is a KML 2.2 document, see https://developers.google.com/kml/documentation/kmlreference.
+type KML struct {
+	XMLName  xml.Name    `xml:"kml"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Document KMLDocument `xml:"Document"`
+}
+
+type KMLDocument struct {
+	Name        string         `xml:"name"`
+	Description string         `xml:"description,omitempty"`
+	Placemarks  []KMLPlacemark `xml:"Placemark"`
+}
+
+type KMLPlacemark struct {
+	Name         string           `xml:"name"`
+	Description  string           `xml:"description,omitempty"`
+	TimeStamp    *KMLTimeStamp    `xml:"TimeStamp,omitempty"`
+	ExtendedData *KMLExtendedData `xml:"ExtendedData,omitempty"`
+	Point        *KMLGeometry     `xml:"Point,omitempty"`
+	LineString   *KMLGeometry     `xml:"LineString,omitempty"`
+}
+
+type KMLTimeStamp struct {
+	When string `xml:"when"`
+}
+
+type KMLExtendedData struct {
+	Data []KMLData `xml:"Data"`
+}
+
+type KMLData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type KMLGeometry struct {
+	Tessellate  int    `xml:"tessellate,omitempty"`
+	Coordinates string `xml:"coordinates"`
+}
+
+// NewKML builds a LineString placemark for the route, when it has at least
+// two points, followed by a Point placemark per media item.
+func NewKML(route TripRoute) KML {
+	kml := KML{
+		Xmlns: "http://www.opengis.net/kml/2.2",
+		Document: KMLDocument{
+			Name:        route.Trip.Name,
+			Description: route.Trip.Description,
+			Placemarks:  []KMLPlacemark{},
+		},
+	}
+
+	if len(route.Points) >= 2 {
+		coordinates := make([]string, len(route.Points))
+		for i, point := range route.Points {
+			coordinates[i] = kmlCoordinates(point)
+		}
+		kml.Document.Placemarks = append(kml.Document.Placemarks, KMLPlacemark{
+			Name:       route.Trip.Name,
+			LineString: &KMLGeometry{Tessellate: 1, Coordinates: strings.Join(coordinates, " ")},
+		})
+	}
+
+	for _, point := range route.Points {
+		placemark := KMLPlacemark{
+			Name:  point.Name,
+			Point: &KMLGeometry{Coordinates: kmlCoordinates(point)},
+			ExtendedData: &KMLExtendedData{Data: []KMLData{
+				{Name: "media_id", Value: strconv.FormatInt(point.MediaID, 10)},
+				{Name: "type", Value: point.Type},
+				{Name: "city", Value: point.City},
+				{Name: "country", Value: point.Country},
+				{Name: "url", Value: point.URL},
+			}},
+		}
+		if point.URL != "" {
+			placemark.Description = fmt.Sprintf(`<a href="%s">Open %s</a>`, point.URL, point.Type)
+		}
+		if !point.Time.IsZero() {
+			when := point.Time.UTC().Format(time.RFC3339)
+			placemark.TimeStamp = &KMLTimeStamp{When: when}
+			placemark.ExtendedData.Data = append(placemark.ExtendedData.Data, KMLData{Name: "capture_date", Value: when})
+		}
+		kml.Document.Placemarks = append(kml.Document.Placemarks, placemark)
+	}
+
+	return kml
+}
+
+// Encode renders the document with its XML declaration.
+func (k KML) Encode() ([]byte, error) {
+	body, err := xml.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// kmlCoordinates formats a point as longitude,latitude[,altitude].
+func kmlCoordinates(point RoutePoint) string {
+	coordinates := strconv.FormatFloat(point.Longitude, 'f', -1, 64) + "," + strconv.FormatFloat(point.Latitude, 'f', -1, 64)
+	if point.Altitude != 0 {
+		coordinates += "," + strconv.FormatFloat(point.Altitude, 'f', -1, 64)
+	}
+	return coordinates
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type VisibilityEnum string
@@ -12,6 +16,22 @@ const (
 	Friends VisibilityEnum = "FRIENDS"
 )
 
+// AtLeastAsOpenAs reports whether everyone who can see something with the
+// other visibility can also see something with v.
+func (v VisibilityEnum) AtLeastAsOpenAs(other VisibilityEnum) bool {
+	return v.openness() >= other.openness()
+}
+
+func (v VisibilityEnum) openness() int {
+	switch v {
+	case Public:
+		return 2
+	case Friends:
+		return 1
+	}
+	return 0
+}
+
 type Media struct {
 	MediaID      int64          `gorm:"primaryKey;autoIncrement"`
 	TripID       int64          `json:"trip_id" gorm:"column:trip_id"`
@@ -25,12 +45,16 @@ type Media struct {
 	GpsLatitude  float64        `json:"gps_latitude"`
 	GpsLongitude float64        `json:"gps_longitude"`
 	GpsAltitude  float64        `json:"gps_altitude"`
+	Version      int            `json:"version" gorm:"default:1"`
+	DeletedAt    gorm.DeletedAt `json:"-"`
 }
 
 type MediaMetadata struct {
 	Type                   string
 	LocationID             int64
 	CaptureDate            time.Time
+	CaptureDateKnown       bool // Set when CaptureDate comes from the EXIF data
+	CaptureOffsetKnown     bool // Set when the UTC offset of CaptureDate is known, otherwise it is the wall clock time as UTC
 	Latitude               float64
 	Longitude              float64
 	Altitude               float64
@@ -39,6 +63,37 @@ type MediaMetadata struct {
 	RequiresManualLocation bool // New field to indicate if manual location is needed
 }
 
+// SetCaptureOffset places a capture date whose UTC offset is unknown in loc.
+// A date with a known offset is left as it is.
+func (m *MediaMetadata) SetCaptureOffset(loc *time.Location) {
+	if !m.CaptureDateKnown || m.CaptureOffsetKnown {
+		return
+	}
+	m.CaptureDate = WallClockIn(m.CaptureDate, loc)
+	m.CaptureOffsetKnown = true
+}
+
+// WallClockIn reads the date and time of t as a time in loc.
+func WallClockIn(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc).UTC()
+}
+
+// ParseUTCOffset reads an offset such as "+02:00", "-0530" or "Z" into a fixed
+// time zone.
+func ParseUTCOffset(offset string) (*time.Location, error) {
+	offset = strings.TrimSpace(offset)
+	if offset == "Z" {
+		return time.UTC, nil
+	}
+	for _, layout := range []string{"-07:00", "-0700", "-07"} {
+		if t, err := time.Parse(layout, offset); err == nil {
+			_, seconds := t.Zone()
+			return time.FixedZone("", seconds), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid UTC offset %q", offset)
+}
+
 type MediaByTrip struct {
 	MediaID   int64   `json:"mediaId"`
 	URL       string  `json:"url"`
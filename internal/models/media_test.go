@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetCaptureOffset(t *testing.T) {
+	wallClock := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		offset   string
+		metadata MediaMetadata
+		want     time.Time
+	}{
+		{
+			name:     "offset east of UTC",
+			offset:   "+02:00",
+			metadata: MediaMetadata{CaptureDate: wallClock, CaptureDateKnown: true},
+			want:     time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "offset without colon west of UTC",
+			offset:   "-0530",
+			metadata: MediaMetadata{CaptureDate: wallClock, CaptureDateKnown: true},
+			want:     time.Date(2024, 6, 1, 15, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "UTC",
+			offset:   "Z",
+			metadata: MediaMetadata{CaptureDate: wallClock, CaptureDateKnown: true},
+			want:     wallClock,
+		},
+		{
+			name:     "offset from EXIF wins",
+			offset:   "+02:00",
+			metadata: MediaMetadata{CaptureDate: wallClock, CaptureDateKnown: true, CaptureOffsetKnown: true},
+			want:     wallClock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := ParseUTCOffset(tt.offset)
+			if err != nil {
+				t.Fatalf("ParseUTCOffset(%q) error = %v", tt.offset, err)
+			}
+			tt.metadata.SetCaptureOffset(loc)
+			if !tt.metadata.CaptureDate.Equal(tt.want) {
+				t.Errorf("CaptureDate = %v, want %v", tt.metadata.CaptureDate, tt.want)
+			}
+			if !tt.metadata.CaptureOffsetKnown {
+				t.Errorf("CaptureOffsetKnown = false, want true")
+			}
+		})
+	}
+}
+
+func TestParseUTCOffsetRejectsInvalidOffsets(t *testing.T) {
+	for _, offset := range []string{"", "2", "+25:00", "Europe/Paris", "+02:00:00"} {
+		if _, err := ParseUTCOffset(offset); err == nil {
+			t.Errorf("ParseUTCOffset(%q) succeeded, want an error", offset)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in minor units (cents), encoded in JSON as a decimal
+// number with up to two decimals.
+type Money int64
+
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	value := strings.Trim(string(b), `"`)
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not an amount", value)
+	}
+	cents := math.Round(parsed * 100)
+	if math.Abs(parsed*100-cents) > 1e-6 {
+		return fmt.Errorf("%q has more than two decimals", value)
+	}
+	*m = Money(cents)
+	return nil
+}
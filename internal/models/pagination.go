@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type TripSort string
+
+const (
+	SortCreated   TripSort = "created"
+	SortStartDate TripSort = "start_date"
+	SortName      TripSort = "name"
+)
+
+// PageRequest describes one page of a keyset-paginated trip list. Range
+// optionally narrows the list to trips overlapping those dates.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	Sort   TripSort
+	Range  DateRange
+}
+
+// PageCursor is the position of the last row of a page. Value holds the sort
+// key and TripID breaks ties between rows sharing it.
+type PageCursor struct {
+	Sort   TripSort `json:"s"`
+	Value  string   `json:"v,omitempty"`
+	TripID int      `json:"id"`
+}
+
+// Normalize applies defaults and rejects unknown sorts and limits.
+func (p *PageRequest) Normalize() error {
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxPageLimit)
+	}
+	if p.Sort == "" {
+		p.Sort = SortCreated
+	}
+	switch p.Sort {
+	case SortCreated, SortStartDate, SortName:
+	default:
+		return fmt.Errorf("sort must be one of created, start_date or name")
+	}
+	return nil
+}
+
+func (c PageCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodePageCursor(cursor string) (*PageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var c PageCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &c, nil
+}
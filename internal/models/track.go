@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// MaxTrackFileSize caps imported track files, in bytes.
+const MaxTrackFileSize = 50 << 20
+
+type TrackFormat string
+
+const (
+	TrackGPX TrackFormat = "gpx"
+	TrackTCX TrackFormat = "tcx"
+	TrackFIT TrackFormat = "fit"
+)
+
+// Track is a GPS recording imported into a trip.
+type Track struct {
+	TrackID    int64       `json:"track_id" gorm:"column:track_id;primaryKey;autoIncrement"`
+	TripID     int         `json:"trip_id" gorm:"column:trip_id"`
+	Name       string      `json:"name" gorm:"column:name"`
+	Format     TrackFormat `json:"format" gorm:"column:format"`
+	StartedAt  *time.Time  `json:"started_at,omitempty" gorm:"column:started_at"`
+	EndedAt    *time.Time  `json:"ended_at,omitempty" gorm:"column:ended_at"`
+	PointCount int         `json:"point_count" gorm:"column:point_count"`
+	DistanceKm float64     `json:"distance_km" gorm:"column:distance_km"`
+	UploadedBy uint        `json:"uploaded_by" gorm:"column:uploaded_by"`
+	CreatedAt  time.Time   `json:"created_at" gorm:"column:created_at"`
+
+	Points []TrackPoint `json:"-" gorm:"-"`
+}
+
+type TrackPoint struct {
+	TrackID   int64      `gorm:"column:track_id;primaryKey"`
+	TripID    int        `gorm:"column:trip_id"`
+	Seq       int        `gorm:"column:seq;primaryKey"`
+	Time      *time.Time `gorm:"column:time"`
+	Latitude  float64    `gorm:"column:latitude"`
+	Longitude float64    `gorm:"column:longitude"`
+	Elevation *float64   `gorm:"column:elevation"`
+}
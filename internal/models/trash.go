@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type TrashedTrip struct {
+	Trip      Trip      `json:"trip"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+type TrashedMedia struct {
+	MediaID   int64     `json:"media_id"`
+	TripID    int64     `json:"trip_id"`
+	Type      string    `json:"type"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// Trash lists what a user deleted and can still restore.
+type Trash struct {
+	Trips []TrashedTrip  `json:"trips"`
+	Media []TrashedMedia `json:"media"`
+}
@@ -1,22 +1,33 @@
 package models
 
+import "gorm.io/gorm"
+
 type Trip struct {
-	TripID      int    `gorm:"primaryKey;autoIncrement"`
-	UserID      uint   `json:"user_id" db:"user_id"`
-	Name        string `json:"name" db:"name"`
-	Description string `json:"description,omitempty" db:"description"`
-	Visibility  string `json:"visibility" db:"visibility" default:"PRIVATE"`
-	StartDate   string `json:"start_date,omitempty" db:"start_date"`
-	EndDate     string `json:"end_date,omitempty" db:"end_date"`
+	TripID       int            `gorm:"primaryKey;autoIncrement"`
+	UserID       uint           `json:"user_id" db:"user_id"`
+	Name         string         `json:"name" db:"name"`
+	Description  string         `json:"description,omitempty" db:"description"`
+	Visibility   string         `json:"visibility" db:"visibility" default:"PRIVATE"`
+	StartDate    *Date          `json:"start_date,omitempty" db:"start_date"`
+	EndDate      *Date          `json:"end_date,omitempty" db:"end_date"`
+	Language     string         `json:"language,omitempty" db:"language" gorm:"default:english"`
+	Category     *string        `json:"category,omitempty" db:"category"`
+	CoverMediaID *int64         `json:"cover_media_id,omitempty" db:"cover_media_id"`
+	Version      int            `json:"version" db:"version" gorm:"default:1"`
+	DeletedAt    gorm.DeletedAt `json:"-" db:"deleted_at"`
+
+	Tags     []string `json:"tags" gorm:"-"`
+	CoverURL string   `json:"cover_url,omitempty" gorm:"-"`
 }
 
 type TripRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Visibility  string `json:"visibility"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date"`
-	AlbumID     string `json:"album_id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Visibility  string   `json:"visibility"`
+	StartDate   string   `json:"start_date"`
+	EndDate     string   `json:"end_date"`
+	Language    string   `json:"language"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags"`
+	AlbumID     string   `json:"album_id"`
 }
-
-
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+const ArchiveContentType = "application/zip"
+
+// ArchiveManifestName is the name of the manifest inside trip archives.
+const ArchiveManifestName = "manifest.json"
+
+// TripArchiveManifest describes a trip backup. It is written last in the
+// archive, after the original media files it lists.
+type TripArchiveManifest struct {
+	ExportedAt time.Time       `json:"exported_at"`
+	Trip       Trip            `json:"trip"`
+	Media      []ArchivedMedia `json:"media"`
+	Locations  []Location      `json:"locations"`
+}
+
+// ArchivedMedia is a media row with the path of its original in the archive.
+// Missing is set when the original could not be read from storage.
+type ArchivedMedia struct {
+	Media
+	File    string `json:"file"`
+	Missing bool   `json:"missing,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// TripCloneOptions controls how a trip is copied into a new one.
+type TripCloneOptions struct {
+	// Name overrides the copied name when set
+	Name string
+	// StartDate moves the copy, and every date of its itinerary, so that it
+	// starts on this day
+	StartDate *Date
+	// IncludeTags copies the source trip's tags
+	IncludeTags bool
+	// IncludeItinerary copies the itinerary days and stops, shifted along
+	// with StartDate
+	IncludeItinerary bool
+	// IncludeMedia references the source media the caller can see from the
+	// copy instead of leaving it empty. The files themselves are shared, not
+	// duplicated.
+	IncludeMedia bool
+}
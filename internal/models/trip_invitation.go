@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationExpired  InvitationStatus = "expired"
+)
+
+type TripInvitation struct {
+	InvitationID int64            `json:"invitation_id" gorm:"column:invitation_id;primaryKey;autoIncrement"`
+	TripID       int              `json:"trip_id" gorm:"column:trip_id"`
+	InviterID    uint             `json:"inviter_id" gorm:"column:inviter_id"`
+	InviteeID    uint             `json:"invitee_id" gorm:"column:invitee_id"`
+	Role         TripRole         `json:"role" gorm:"column:role"`
+	Status       InvitationStatus `json:"status" gorm:"column:status"`
+	CreatedAt    time.Time        `json:"created_at" gorm:"column:created_at"`
+	ExpiresAt    time.Time        `json:"expires_at" gorm:"column:expires_at"`
+	RespondedAt  *time.Time       `json:"responded_at,omitempty" gorm:"column:responded_at"`
+}
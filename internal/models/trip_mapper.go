@@ -1,57 +1,144 @@
 package models
 
+import "fmt"
+
 type TripMapper struct{}
 
 func (m *TripMapper) ToTrip(req struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Visibility  string `json:"visibility"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date"`
-	AlbumID     any    `json:"album_id"`
-}, tokenResponse interface{}) Trip {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Visibility  string   `json:"visibility"`
+	StartDate   string   `json:"start_date"`
+	EndDate     string   `json:"end_date"`
+	Language    string   `json:"language"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags"`
+	AlbumID     any      `json:"album_id"`
+}, tokenResponse interface{}) (Trip, error) {
+	startDate, endDate, err := m.parseDates(req.StartDate, req.EndDate)
+	if err != nil {
+		return Trip{}, err
+	}
+	category, tags, err := m.parseClassification(req.Category, req.Tags)
+	if err != nil {
+		return Trip{}, err
+	}
 	return Trip{
 		Name:        req.Name,
 		Description: req.Description,
 		Visibility:  req.Visibility,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Language:    req.Language,
+		Category:    category,
+		Tags:        tags,
 		UserID:      tokenResponse.(uint),
-	}
+	}, nil
 }
 func (m *TripMapper) ToTripUpdate(req struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Visibility  string `json:"visibility"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date"`
-}, tokenResponse interface{}) Trip {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Visibility  string   `json:"visibility"`
+	StartDate   string   `json:"start_date"`
+	EndDate     string   `json:"end_date"`
+	Language    string   `json:"language"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags"`
+}, tokenResponse interface{}) (Trip, error) {
+	startDate, endDate, err := m.parseDates(req.StartDate, req.EndDate)
+	if err != nil {
+		return Trip{}, err
+	}
+	category, tags, err := m.parseClassification(req.Category, req.Tags)
+	if err != nil {
+		return Trip{}, err
+	}
 	return Trip{
 		TripID:      req.ID,
 		Name:        req.Name,
 		Description: req.Description,
 		Visibility:  req.Visibility,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Language:    req.Language,
+		Category:    category,
+		Tags:        tags,
 		UserID:      tokenResponse.(uint),
-	}
+	}, nil
 }
 
 func (m *TripMapper) ToTripRequest(req struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Visibility  string `json:"visibility"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date"`
-	AlbumID     any    `json:"album_id"`
-}, userID uint) Trip {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Visibility  string   `json:"visibility"`
+	StartDate   string   `json:"start_date"`
+	EndDate     string   `json:"end_date"`
+	Language    string   `json:"language"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags"`
+	AlbumID     any      `json:"album_id"`
+}, userID uint) (Trip, error) {
+	startDate, endDate, err := m.parseDates(req.StartDate, req.EndDate)
+	if err != nil {
+		return Trip{}, err
+	}
+	category, tags, err := m.parseClassification(req.Category, req.Tags)
+	if err != nil {
+		return Trip{}, err
+	}
 	return Trip{
 		UserID:      userID,
 		Name:        req.Name,
 		Description: req.Description,
 		Visibility:  req.Visibility,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Language:    req.Language,
+		Category:    category,
+		Tags:        tags,
+	}, nil
+}
+
+// parseDates validates the optional ISO 8601 start and end dates of a trip.
+func (m *TripMapper) parseDates(start, end string) (*Date, *Date, error) {
+	var startDate, endDate *Date
+	if start != "" {
+		d, err := ParseDate(start)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid start_date: %w", err)
+		}
+		startDate = &d
+	}
+	if end != "" {
+		d, err := ParseDate(end)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid end_date: %w", err)
+		}
+		endDate = &d
+	}
+	if startDate != nil && endDate != nil && endDate.Before(startDate.Time) {
+		return nil, nil, fmt.Errorf("start_date must not be after end_date")
 	}
-}
\ No newline at end of file
+	return startDate, endDate, nil
+}
+
+// parseClassification validates the category against the curated list and
+// normalizes the tags. Nil tags are kept nil so updates leave them untouched.
+func (m *TripMapper) parseClassification(category string, tags []string) (*string, []string, error) {
+	var categoryPtr *string
+	if category != "" {
+		if !IsTripCategory(category) {
+			return nil, nil, fmt.Errorf("unknown category %q", category)
+		}
+		categoryPtr = &category
+	}
+	if tags == nil {
+		return categoryPtr, nil, nil
+	}
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	return categoryPtr, normalized, nil
+}
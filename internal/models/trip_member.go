@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type TripRole string
+
+const (
+	RoleOwner  TripRole = "owner"
+	RoleEditor TripRole = "editor"
+	RoleViewer TripRole = "viewer"
+)
+
+// TripMember grants a user a role on somebody else's trip. The trip creator
+// (Trip.UserID) is always an owner and has no row of their own.
+type TripMember struct {
+	TripID  int       `json:"trip_id" gorm:"column:trip_id;primaryKey"`
+	UserID  uint      `json:"user_id" gorm:"column:user_id;primaryKey"`
+	Role    TripRole  `json:"role" gorm:"column:role"`
+	AddedBy uint      `json:"added_by" gorm:"column:added_by"`
+	AddedAt time.Time `json:"added_at" gorm:"column:added_at"`
+}
+
+func (r TripRole) IsValid() bool {
+	return r == RoleOwner || r == RoleEditor || r == RoleViewer
+}
+
+func (r TripRole) CanEdit() bool {
+	return r == RoleOwner || r == RoleEditor
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const MergePatchContentType = "application/merge-patch+json"
+
+// TripPatch is a JSON merge patch (RFC 7396) of a trip: fields left out are
+// untouched and null clears a field, or resets it to its default.
+type TripPatch map[string]json.RawMessage
+
+// Apply patches trip in place and returns the columns to store. Tags are not
+// a column of trips.trips, tagsChanged reports whether they were patched.
+func (p TripPatch) Apply(trip *Trip) (fields map[string]any, tagsChanged bool, err error) {
+	fields = make(map[string]any)
+	for field, raw := range p {
+		null := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+
+		switch field {
+		case "name":
+			if null {
+				return nil, false, fmt.Errorf("name cannot be cleared")
+			}
+			if err := json.Unmarshal(raw, &trip.Name); err != nil {
+				return nil, false, fmt.Errorf("invalid name: %w", err)
+			}
+			if strings.TrimSpace(trip.Name) == "" {
+				return nil, false, fmt.Errorf("name cannot be empty")
+			}
+			fields["name"] = trip.Name
+
+		case "description":
+			trip.Description = ""
+			if !null {
+				if err := json.Unmarshal(raw, &trip.Description); err != nil {
+					return nil, false, fmt.Errorf("invalid description: %w", err)
+				}
+			}
+			fields["description"] = trip.Description
+
+		case "visibility":
+			if null {
+				return nil, false, fmt.Errorf("visibility cannot be cleared")
+			}
+			if err := json.Unmarshal(raw, &trip.Visibility); err != nil {
+				return nil, false, fmt.Errorf("invalid visibility: %w", err)
+			}
+			switch VisibilityEnum(trip.Visibility) {
+			case Public, Friends, Private:
+			default:
+				return nil, false, fmt.Errorf("visibility must be PUBLIC, FRIENDS or PRIVATE")
+			}
+			fields["visibility"] = trip.Visibility
+
+		case "start_date", "end_date":
+			var date *Date
+			if !null {
+				var value string
+				if err := json.Unmarshal(raw, &value); err != nil {
+					return nil, false, fmt.Errorf("invalid %s: %w", field, err)
+				}
+				parsed, err := ParseDate(value)
+				if err != nil {
+					return nil, false, fmt.Errorf("invalid %s: %w", field, err)
+				}
+				date = &parsed
+			}
+			if field == "start_date" {
+				trip.StartDate = date
+			} else {
+				trip.EndDate = date
+			}
+			fields[field] = date
+
+		case "language":
+			trip.Language = "english"
+			if !null {
+				if err := json.Unmarshal(raw, &trip.Language); err != nil {
+					return nil, false, fmt.Errorf("invalid language: %w", err)
+				}
+				if !SearchLanguages[trip.Language] {
+					return nil, false, fmt.Errorf("unsupported language %q", trip.Language)
+				}
+			}
+			fields["language"] = trip.Language
+
+		case "category":
+			trip.Category = nil
+			if !null {
+				var category string
+				if err := json.Unmarshal(raw, &category); err != nil {
+					return nil, false, fmt.Errorf("invalid category: %w", err)
+				}
+				if !IsTripCategory(category) {
+					return nil, false, fmt.Errorf("unknown category %q", category)
+				}
+				trip.Category = &category
+			}
+			fields["category"] = trip.Category
+
+		case "tags":
+			var tags []string
+			if !null {
+				if err := json.Unmarshal(raw, &tags); err != nil {
+					return nil, false, fmt.Errorf("invalid tags: %w", err)
+				}
+			}
+			normalized, err := NormalizeTags(tags)
+			if err != nil {
+				return nil, false, err
+			}
+			trip.Tags = normalized
+			tagsChanged = true
+
+		default:
+			return nil, false, fmt.Errorf("field %q cannot be patched", field)
+		}
+	}
+
+	if trip.StartDate != nil && trip.EndDate != nil && trip.EndDate.Before(trip.StartDate.Time) {
+		return nil, false, fmt.Errorf("start_date must not be after end_date")
+	}
+	return fields, tagsChanged, nil
+}
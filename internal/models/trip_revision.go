@@ -0,0 +1,110 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TripRevision records one update of a trip as the fields it changed.
+type TripRevision struct {
+	RevisionID int64        `json:"-" gorm:"column:revision_id;primaryKey;autoIncrement"`
+	TripID     int          `json:"trip_id" gorm:"column:trip_id"`
+	Revision   int          `json:"revision" gorm:"column:revision"`
+	UserID     uint         `json:"user_id" gorm:"column:user_id"`
+	CreatedAt  time.Time    `json:"created_at" gorm:"column:created_at"`
+	Changes    FieldChanges `json:"changes" gorm:"column:changes;type:jsonb"`
+	RevertOf   *int         `json:"revert_of,omitempty" gorm:"column:revert_of"`
+}
+
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+type FieldChanges []FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+func (c *FieldChanges) Scan(value any) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case nil:
+		*c = nil
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into FieldChanges", value)
+}
+
+// revisionFields are the trip fields tracked by revisions, in diff order.
+var revisionFields = []string{"name", "description", "visibility", "start_date", "end_date", "language", "category", "tags"}
+
+func revisionValues(t Trip) map[string]any {
+	tags := t.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return map[string]any{
+		"name":        t.Name,
+		"description": t.Description,
+		"visibility":  t.Visibility,
+		"start_date":  t.StartDate,
+		"end_date":    t.EndDate,
+		"language":    t.Language,
+		"category":    t.Category,
+		"tags":        tags,
+	}
+}
+
+// DiffTrips lists the tracked fields whose value differs between before and after.
+func DiffTrips(before, after Trip) FieldChanges {
+	beforeValues, afterValues := revisionValues(before), revisionValues(after)
+	changes := FieldChanges{}
+	for _, field := range revisionFields {
+		b, _ := json.Marshal(beforeValues[field])
+		a, _ := json.Marshal(afterValues[field])
+		if !bytes.Equal(b, a) {
+			changes = append(changes, FieldChange{Field: field, Before: b, After: a})
+		}
+	}
+	return changes
+}
+
+// ApplyBefore sets the field of trip back to the value it had before the
+// change. Tags are set on trip.Tags.
+func (c FieldChange) ApplyBefore(trip *Trip) error {
+	var target any
+	switch c.Field {
+	case "name":
+		target = &trip.Name
+	case "description":
+		target = &trip.Description
+	case "visibility":
+		target = &trip.Visibility
+	case "start_date":
+		trip.StartDate = nil
+		target = &trip.StartDate
+	case "end_date":
+		trip.EndDate = nil
+		target = &trip.EndDate
+	case "language":
+		target = &trip.Language
+	case "category":
+		trip.Category = nil
+		target = &trip.Category
+	case "tags":
+		trip.Tags = nil
+		target = &trip.Tags
+	default:
+		return fmt.Errorf("unknown revision field %q", c.Field)
+	}
+	return json.Unmarshal(c.Before, target)
+}
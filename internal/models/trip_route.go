@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// TripRoute is a trip's geotagged media in the order they were taken, as
+// exported to GPS formats.
+type TripRoute struct {
+	Trip   Trip
+	Points []RoutePoint
+}
+
+type RoutePoint struct {
+	MediaID   int64
+	Type      string
+	Latitude  float64
+	Longitude float64
+	Altitude  float64
+	Time      time.Time
+	City      string
+	Country   string
+	// Name describes the point, e.g. "Photo 3 – Lyon, France"
+	Name string
+	// URL is a presigned link to the file
+	URL string
+}
+
+func (p RoutePoint) IsPhoto() bool {
+	return p.Type == "photo"
+}
@@ -0,0 +1,30 @@
+package models
+
+// SearchLanguages are the Postgres text search configurations a trip can be
+// stemmed with.
+var SearchLanguages = map[string]bool{
+	"simple":     true,
+	"english":    true,
+	"spanish":    true,
+	"french":     true,
+	"german":     true,
+	"italian":    true,
+	"portuguese": true,
+}
+
+type TripSearchFilters struct {
+	Query      string
+	Language   string
+	Range      DateRange
+	Country    string
+	Visibility string
+	OwnerID    uint
+	Limit      int
+}
+
+type TripSearchResult struct {
+	Trip                 `gorm:"embedded"`
+	Rank                 float64 `json:"rank" gorm:"column:rank"`
+	NameHighlight        string  `json:"name_highlight" gorm:"column:name_highlight"`
+	DescriptionHighlight string  `json:"description_highlight" gorm:"column:description_highlight"`
+}
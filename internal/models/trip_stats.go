@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// TripStats summarises a trip from the media the viewer is allowed to see.
+type TripStats struct {
+	TripID       int        `json:"trip_id"`
+	DistanceKm   float64    `json:"distance_km"`
+	Countries    []string   `json:"countries"`
+	Cities       []string   `json:"cities"`
+	DurationDays int        `json:"duration_days"`
+	FirstCapture *time.Time `json:"first_capture,omitempty"`
+	LastCapture  *time.Time `json:"last_capture,omitempty"`
+	PhotoCount   int        `json:"photo_count"`
+	VideoCount   int        `json:"video_count"`
+	MinAltitude  *float64   `json:"min_altitude,omitempty"`
+	MaxAltitude  *float64   `json:"max_altitude,omitempty"`
+}
+
+// UserStats aggregates the trips of a user that the viewer is allowed to see.
+type UserStats struct {
+	UserID          uint           `json:"user_id"`
+	TripCount       int            `json:"trip_count"`
+	TotalCountries  int            `json:"total_countries"`
+	TotalCities     int            `json:"total_cities"`
+	TotalDistanceKm float64        `json:"total_distance_km"`
+	TripsPerYear    map[int]int    `json:"trips_per_year"`
+	LongestTrip     *TripDuration  `json:"longest_trip,omitempty"`
+	CountryVisits   []CountryVisit `json:"country_visits"`
+}
+
+type TripDuration struct {
+	TripID       int    `json:"trip_id"`
+	Name         string `json:"name"`
+	DurationDays int    `json:"duration_days"`
+}
+
+type CountryVisit struct {
+	Country    string `json:"country"`
+	FirstVisit *Date  `json:"first_visit"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MaxTagsPerTrip = 20
+	MaxTagLength   = 40
+)
+
+// TripCategories is the curated set of categories a trip can belong to.
+var TripCategories = []string{
+	"road_trip",
+	"hiking",
+	"city_break",
+	"beach",
+	"backpacking",
+	"camping",
+	"cruise",
+	"ski",
+	"cultural",
+	"food",
+	"adventure",
+	"family",
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+func IsTripCategory(category string) bool {
+	for _, c := range TripCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTag lowercases a tag and strips surrounding spaces and a leading '#'.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// NormalizeTags normalizes and deduplicates tags, keeping their order.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxTagsPerTrip {
+		return nil, fmt.Errorf("a trip can have at most %d tags", MaxTagsPerTrip)
+	}
+	return normalized, nil
+}
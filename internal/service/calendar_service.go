@@ -0,0 +1,171 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"main/internal/db"
+	"main/internal/models"
+	"time"
+)
+
+// calendarUIDDomain makes the UIDs of feed events globally unique.
+const calendarUIDDomain = "nostos-globe.me"
+
+type CalendarService struct {
+	CalendarRepo  *db.CalendarTokensRepository
+	ItineraryRepo *db.ItineraryRepository
+	TripService   *TripService
+}
+
+// CreateToken returns a new feed token. Only its hash is stored, so the token
+// cannot be shown again.
+func (s *CalendarService) CreateToken(userID uint) (string, *models.CalendarToken, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	value := base64.RawURLEncoding.EncodeToString(secret)
+
+	token := &models.CalendarToken{UserID: userID, TokenHash: hashCalendarToken(value)}
+	if err := s.CalendarRepo.CreateToken(token); err != nil {
+		return "", nil, fmt.Errorf("failed to save token: %w", err)
+	}
+	return value, token, nil
+}
+
+func (s *CalendarService) GetTokens(userID uint) ([]models.CalendarToken, error) {
+	tokens, err := s.CalendarRepo.GetTokensByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (s *CalendarService) RevokeToken(tokenID int64, userID uint) error {
+	token, err := s.CalendarRepo.GetTokenByID(tokenID)
+	if err != nil {
+		return err
+	}
+	if token.UserID != userID {
+		return ErrNotAuthorized
+	}
+	return s.CalendarRepo.RevokeToken(tokenID)
+}
+
+// GetFeed builds the calendar of the token's owner: an all-day event per
+// trip they own or belong to that has a start date, and an event per
+// itinerary stop with a planned start.
+func (s *CalendarService) GetFeed(value string) (*models.Calendar, error) {
+	token, err := s.CalendarRepo.GetActiveTokenByHash(hashCalendarToken(value))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CalendarRepo.TouchToken(token.TokenID, time.Now()); err != nil {
+		fmt.Printf("Error: Failed to record the use of calendar token %d - %v\n", token.TokenID, err)
+	}
+
+	trips, err := s.userTrips(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &models.Calendar{Name: "Nostos trips", GeneratedAt: time.Now(), Events: []models.CalendarEvent{}}
+	tripNames := make(map[int]string)
+	var tripIDs []int
+	for _, trip := range trips {
+		tripNames[trip.TripID] = trip.Name
+		tripIDs = append(tripIDs, trip.TripID)
+		if trip.StartDate == nil {
+			continue
+		}
+
+		// All-day events end on the day after the last one
+		last := trip.StartDate.Time
+		if trip.EndDate != nil && trip.EndDate.After(last) {
+			last = trip.EndDate.Time
+		}
+		end := last.AddDate(0, 0, 1)
+		calendar.Events = append(calendar.Events, models.CalendarEvent{
+			UID:         fmt.Sprintf("trip-%d@%s", trip.TripID, calendarUIDDomain),
+			Summary:     trip.Name,
+			Description: trip.Description,
+			Start:       trip.StartDate.Time,
+			End:         &end,
+			AllDay:      true,
+		})
+	}
+
+	stops, err := s.ItineraryRepo.GetScheduledStopsByTripIDs(tripIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get itinerary stops: %w", err)
+	}
+	locations, err := s.stopLocations(stops)
+	if err != nil {
+		return nil, err
+	}
+	for _, stop := range stops {
+		event := models.CalendarEvent{
+			UID:         fmt.Sprintf("stop-%d@%s", stop.StopID, calendarUIDDomain),
+			Summary:     fmt.Sprintf("%s (%s)", stop.Name, tripNames[stop.TripID]),
+			Description: stop.Notes,
+			Start:       *stop.PlannedStart,
+			End:         stop.PlannedEnd,
+		}
+		if stop.LocationID != nil {
+			event.Location = locations[*stop.LocationID].Name
+		}
+		calendar.Events = append(calendar.Events, event)
+	}
+
+	return calendar, nil
+}
+
+// userTrips are the trips the user owns or is a member of.
+func (s *CalendarService) userTrips(userID uint) ([]models.Trip, error) {
+	owned, err := s.TripService.TripRepo.GetUserTripsWithVisibility(userID, []string{
+		string(models.Public), string(models.Friends), string(models.Private),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trips: %w", err)
+	}
+	shared, err := s.TripService.MemberRepo.GetTripsByMember(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared trips: %w", err)
+	}
+
+	seen := make(map[int]bool)
+	var trips []models.Trip
+	for _, trip := range append(owned, shared...) {
+		if !seen[trip.TripID] {
+			seen[trip.TripID] = true
+			trips = append(trips, trip)
+		}
+	}
+	return trips, nil
+}
+
+func (s *CalendarService) stopLocations(stops []models.ItineraryStop) (map[int64]models.Location, error) {
+	var locationIDs []int64
+	for _, stop := range stops {
+		if stop.LocationID != nil {
+			locationIDs = append(locationIDs, *stop.LocationID)
+		}
+	}
+	locations, err := s.ItineraryRepo.GetLocationsByIDs(locationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stop locations: %w", err)
+	}
+	locationsByID := make(map[int64]models.Location)
+	for _, location := range locations {
+		locationsByID[location.LocationID] = location
+	}
+	return locationsByID, nil
+}
+
+func hashCalendarToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"fmt"
+	"main/internal/db"
+	"main/internal/models"
+	"strings"
+	"time"
+)
+
+type ChecklistService struct {
+	ChecklistRepo *db.ChecklistsRepository
+	TripService   *TripService
+}
+
+func (s *ChecklistService) GetChecklists(tripID int, userID uint) ([]models.Checklist, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.TripService.CanViewTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+
+	checklists, err := s.ChecklistRepo.GetChecklistsByTripID(tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklists: %w", err)
+	}
+
+	items, err := s.ChecklistRepo.GetItemsByTripID(tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist items: %w", err)
+	}
+
+	itemsByChecklist := make(map[int64][]models.ChecklistItem)
+	for _, item := range items {
+		itemsByChecklist[item.ChecklistID] = append(itemsByChecklist[item.ChecklistID], item)
+	}
+	for i := range checklists {
+		checklists[i].Items = itemsByChecklist[checklists[i].ChecklistID]
+		if checklists[i].Items == nil {
+			checklists[i].Items = []models.ChecklistItem{}
+		}
+	}
+
+	return checklists, nil
+}
+
+// CreateChecklist adds a checklist to the trip. With a template the title
+// defaults to the template's and its items are copied in.
+func (s *ChecklistService) CreateChecklist(tripID int, userID uint, title string, templateID *int64) (*models.Checklist, error) {
+	if _, err := s.editableTrip(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	checklist := models.Checklist{
+		TripID:    tripID,
+		Title:     strings.TrimSpace(title),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		Items:     []models.ChecklistItem{},
+	}
+
+	if templateID != nil {
+		template, err := s.ChecklistRepo.GetTemplateByID(*templateID)
+		if err != nil {
+			return nil, err
+		}
+		if template.UserID != userID {
+			return nil, ErrNotAuthorized
+		}
+		if checklist.Title == "" {
+			checklist.Title = template.Title
+		}
+		for _, text := range template.Items {
+			checklist.Items = append(checklist.Items, models.ChecklistItem{Text: text})
+		}
+	}
+
+	if checklist.Title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidInput)
+	}
+
+	if err := s.ChecklistRepo.CreateChecklist(&checklist); err != nil {
+		return nil, fmt.Errorf("failed to create checklist: %w", err)
+	}
+
+	return &checklist, nil
+}
+
+func (s *ChecklistService) DeleteChecklist(tripID int, checklistID int64, userID uint) error {
+	if _, err := s.editableTrip(tripID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.getChecklistInTrip(tripID, checklistID); err != nil {
+		return err
+	}
+
+	return s.ChecklistRepo.DeleteChecklist(checklistID)
+}
+
+func (s *ChecklistService) CreateItem(tripID int, checklistID int64, userID uint, item models.ChecklistItem) (*models.ChecklistItem, error) {
+	trip, err := s.editableTrip(tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getChecklistInTrip(tripID, checklistID); err != nil {
+		return nil, err
+	}
+
+	item.Text = strings.TrimSpace(item.Text)
+	if item.Text == "" {
+		return nil, fmt.Errorf("%w: item text is required", ErrInvalidInput)
+	}
+	if item.AssigneeID != nil {
+		if err := s.checkAssignee(trip, *item.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+
+	item.TripID = tripID
+	item.ChecklistID = checklistID
+	item.Checked = false
+	item.CheckedBy = nil
+	item.CheckedAt = nil
+	if err := s.ChecklistRepo.CreateItem(&item); err != nil {
+		return nil, fmt.Errorf("failed to create checklist item: %w", err)
+	}
+
+	return &item, nil
+}
+
+// UpdateItem renames, checks or (re)assigns an item. Checking records who
+// did it and when.
+func (s *ChecklistService) UpdateItem(tripID int, checklistID int64, itemID int64, userID uint, update models.ChecklistItemUpdate) (*models.ChecklistItem, error) {
+	trip, err := s.editableTrip(tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.getItemInChecklist(tripID, checklistID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]any)
+	if update.Text != nil {
+		text := strings.TrimSpace(*update.Text)
+		if text == "" {
+			return nil, fmt.Errorf("%w: item text is required", ErrInvalidInput)
+		}
+		fields["text"] = text
+	}
+	if update.Unassign {
+		fields["assignee_id"] = nil
+	} else if update.Assignee != nil {
+		if err := s.checkAssignee(trip, *update.Assignee); err != nil {
+			return nil, err
+		}
+		fields["assignee_id"] = *update.Assignee
+	}
+	if update.Checked != nil && *update.Checked != item.Checked {
+		fields["checked"] = *update.Checked
+		if *update.Checked {
+			fields["checked_by"] = userID
+			fields["checked_at"] = time.Now()
+		} else {
+			fields["checked_by"] = nil
+			fields["checked_at"] = nil
+		}
+	}
+
+	if len(fields) > 0 {
+		if err := s.ChecklistRepo.UpdateItem(itemID, fields); err != nil {
+			return nil, fmt.Errorf("failed to update checklist item: %w", err)
+		}
+	}
+
+	return s.ChecklistRepo.GetItemByID(itemID)
+}
+
+func (s *ChecklistService) DeleteItem(tripID int, checklistID int64, itemID int64, userID uint) error {
+	if _, err := s.editableTrip(tripID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.getItemInChecklist(tripID, checklistID, itemID); err != nil {
+		return err
+	}
+
+	return s.ChecklistRepo.DeleteItem(itemID)
+}
+
+// ReorderItems expects itemIDs to contain every item of the checklist exactly once.
+func (s *ChecklistService) ReorderItems(tripID int, checklistID int64, userID uint, itemIDs []int64) ([]models.ChecklistItem, error) {
+	if _, err := s.editableTrip(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getChecklistInTrip(tripID, checklistID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.ChecklistRepo.GetItemsByChecklistID(checklistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist items: %w", err)
+	}
+
+	if len(current) != len(itemIDs) {
+		return nil, fmt.Errorf("%w: expected %d item IDs, got %d", ErrInvalidInput, len(current), len(itemIDs))
+	}
+	remaining := make(map[int64]bool)
+	for _, item := range current {
+		remaining[item.ItemID] = true
+	}
+	for _, itemID := range itemIDs {
+		if !remaining[itemID] {
+			return nil, fmt.Errorf("%w: item %d is missing, duplicated or not part of this checklist", ErrInvalidInput, itemID)
+		}
+		delete(remaining, itemID)
+	}
+
+	if err := s.ChecklistRepo.ReorderItems(checklistID, itemIDs); err != nil {
+		return nil, fmt.Errorf("failed to reorder checklist items: %w", err)
+	}
+
+	return s.ChecklistRepo.GetItemsByChecklistID(checklistID)
+}
+
+func (s *ChecklistService) GetTemplates(userID uint) ([]models.ChecklistTemplate, error) {
+	return s.ChecklistRepo.GetTemplatesByUserID(userID)
+}
+
+// CreateTemplate saves a reusable checklist for the user, either from the
+// given items or from one of the trips' checklists the user can see.
+func (s *ChecklistService) CreateTemplate(userID uint, title string, items []string, fromChecklistID *int64) (*models.ChecklistTemplate, error) {
+	template := models.ChecklistTemplate{
+		UserID:    userID,
+		Title:     strings.TrimSpace(title),
+		CreatedAt: time.Now(),
+		Items:     []string{},
+	}
+
+	if fromChecklistID != nil {
+		checklist, err := s.ChecklistRepo.GetChecklistByID(*fromChecklistID)
+		if err != nil {
+			return nil, err
+		}
+		trip, err := s.TripService.TripRepo.GetTripByID(checklist.TripID)
+		if err != nil {
+			return nil, err
+		}
+		canView, err := s.TripService.CanViewTrip(trip, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			return nil, ErrNotAuthorized
+		}
+		checklistItems, err := s.ChecklistRepo.GetItemsByChecklistID(checklist.ChecklistID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get checklist items: %w", err)
+		}
+		if template.Title == "" {
+			template.Title = checklist.Title
+		}
+		for _, item := range checklistItems {
+			items = append(items, item.Text)
+		}
+	}
+
+	for _, text := range items {
+		if text = strings.TrimSpace(text); text != "" {
+			template.Items = append(template.Items, text)
+		}
+	}
+	if template.Title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidInput)
+	}
+
+	if err := s.ChecklistRepo.CreateTemplate(&template); err != nil {
+		return nil, fmt.Errorf("failed to create checklist template: %w", err)
+	}
+
+	return &template, nil
+}
+
+func (s *ChecklistService) DeleteTemplate(templateID int64, userID uint) error {
+	template, err := s.ChecklistRepo.GetTemplateByID(templateID)
+	if err != nil {
+		return err
+	}
+	if template.UserID != userID {
+		return ErrNotAuthorized
+	}
+
+	return s.ChecklistRepo.DeleteTemplate(templateID)
+}
+
+func (s *ChecklistService) editableTrip(tripID int, userID uint) (models.Trip, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	canEdit, err := s.TripService.CanEditTrip(trip, userID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	if !canEdit {
+		return models.Trip{}, ErrNotAuthorized
+	}
+	return trip, nil
+}
+
+// checkAssignee only lets items be assigned to the trip's participants.
+func (s *ChecklistService) checkAssignee(trip models.Trip, assigneeID uint) error {
+	participants, err := s.TripService.Participants(trip)
+	if err != nil {
+		return err
+	}
+	if !participants[assigneeID] {
+		return fmt.Errorf("%w: user %d is not part of this trip", ErrInvalidInput, assigneeID)
+	}
+	return nil
+}
+
+func (s *ChecklistService) getChecklistInTrip(tripID int, checklistID int64) (*models.Checklist, error) {
+	checklist, err := s.ChecklistRepo.GetChecklistByID(checklistID)
+	if err != nil {
+		return nil, err
+	}
+	if checklist.TripID != tripID {
+		return nil, fmt.Errorf("%w: checklist does not belong to this trip", ErrInvalidInput)
+	}
+	return checklist, nil
+}
+
+func (s *ChecklistService) getItemInChecklist(tripID int, checklistID int64, itemID int64) (*models.ChecklistItem, error) {
+	item, err := s.ChecklistRepo.GetItemByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.TripID != tripID || item.ChecklistID != checklistID {
+		return nil, fmt.Errorf("%w: item does not belong to this checklist", ErrInvalidInput)
+	}
+	return item, nil
+}
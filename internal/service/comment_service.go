@@ -0,0 +1,276 @@
+package service
+
+import (
+	"fmt"
+	"main/internal/db"
+	"main/internal/events"
+	"main/internal/models"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type CommentService struct {
+	CommentRepo  *db.CommentsRepository
+	TripService  *TripService
+	MediaService *MediaService
+	Events       *events.Publisher
+}
+
+// commentTarget is what a comment is left on: a trip, or one of its media.
+type commentTarget struct {
+	trip    models.Trip
+	mediaID *int64
+}
+
+// GetComments returns a page of threads on the trip, or on the media when
+// mediaID is set.
+func (s *CommentService) GetComments(tripID int, mediaID *int64, userID uint, limit int, cursor string) (*models.CommentPage, error) {
+	target, err := s.visibleTarget(tripID, mediaID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit == 0 {
+		limit = models.DefaultPageLimit
+	}
+	if limit < 0 || limit > models.MaxPageLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, models.MaxPageLimit)
+	}
+	var afterID int64
+	if cursor != "" {
+		c, err := models.DecodeCommentCursor(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		afterID = c.CommentID
+	}
+
+	// Fetch one extra thread to know whether another page follows
+	roots, replies, err := s.CommentRepo.GetThreads(target.trip.TripID, target.mediaID, afterID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	page := &models.CommentPage{}
+	if len(roots) > limit {
+		roots = roots[:limit]
+		page.NextCursor = models.CommentCursor{CommentID: roots[limit-1].CommentID}.Encode()
+	}
+	page.Comments = buildThreads(roots, replies)
+
+	return page, nil
+}
+
+// CreateComment adds a comment, or a reply when parentID is set. Anyone who
+// can see the trip or media may comment on it.
+func (s *CommentService) CreateComment(tripID int, mediaID *int64, userID uint, parentID *int64, body string) (*models.Comment, error) {
+	target, err := s.visibleTarget(tripID, mediaID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err = validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := models.Comment{
+		TripID:    target.trip.TripID,
+		MediaID:   target.mediaID,
+		AuthorID:  userID,
+		Body:      body,
+		CreatedAt: time.Now(),
+		Replies:   []models.Comment{},
+	}
+
+	var parent *models.Comment
+	if parentID != nil {
+		parent, err = s.getCommentOnTarget(target, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.IsDeleted() {
+			return nil, fmt.Errorf("%w: cannot reply to a deleted comment", ErrInvalidInput)
+		}
+		comment.ParentID = &parent.CommentID
+		comment.RootID = parent.RootID
+	}
+
+	if err := s.CommentRepo.CreateComment(&comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	if s.Events != nil {
+		evt := events.CommentCreatedEvent{
+			CommentID:   comment.CommentID,
+			TripID:      comment.TripID,
+			MediaID:     comment.MediaID,
+			ParentID:    comment.ParentID,
+			AuthorID:    comment.AuthorID,
+			TripOwnerID: target.trip.UserID,
+			CreatedAt:   comment.CreatedAt,
+		}
+		if parent != nil {
+			evt.ParentAuthorID = &parent.AuthorID
+		}
+		_ = s.Events.Publish("comment.created", evt)
+	}
+
+	return &comment, nil
+}
+
+// EditComment lets authors change their own comments.
+func (s *CommentService) EditComment(tripID int, mediaID *int64, commentID int64, userID uint, body string) (*models.Comment, error) {
+	target, err := s.visibleTarget(tripID, mediaID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.getCommentOnTarget(target, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, ErrNotAuthorized
+	}
+	if comment.IsDeleted() {
+		return nil, fmt.Errorf("%w: comment has been deleted", ErrInvalidInput)
+	}
+
+	body, err = validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.CommentRepo.UpdateComment(commentID, map[string]any{"body": body, "edited_at": now}); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	comment.Body = body
+	comment.EditedAt = &now
+	comment.Replies = []models.Comment{}
+
+	return comment, nil
+}
+
+// DeleteComment removes a comment's body while keeping its replies. Authors
+// delete their own comments and the trip's owners moderate any comment on it.
+func (s *CommentService) DeleteComment(tripID int, mediaID *int64, commentID int64, userID uint) error {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return err
+	}
+	target := commentTarget{trip: trip, mediaID: mediaID}
+
+	comment, err := s.getCommentOnTarget(target, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID {
+		role, err := s.TripService.GetUserRole(trip, userID)
+		if err != nil {
+			return err
+		}
+		if role != models.RoleOwner {
+			return ErrNotAuthorized
+		}
+	}
+	if comment.IsDeleted() {
+		return nil
+	}
+
+	return s.CommentRepo.UpdateComment(commentID, map[string]any{
+		"body":       "",
+		"deleted_at": time.Now(),
+		"deleted_by": userID,
+	})
+}
+
+// MediaTripID returns the trip a media item belongs to, for the media
+// comment endpoints which are not nested under a trip.
+func (s *CommentService) MediaTripID(mediaID int64) (int, error) {
+	media, err := s.MediaService.GetMediaByID(mediaID)
+	if err != nil {
+		return 0, err
+	}
+	return int(media.TripID), nil
+}
+
+// visibleTarget checks the user can see the trip and, for media comments,
+// the media itself.
+func (s *CommentService) visibleTarget(tripID int, mediaID *int64, userID uint) (commentTarget, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return commentTarget{}, err
+	}
+	canView, err := s.TripService.CanViewTrip(trip, userID)
+	if err != nil {
+		return commentTarget{}, err
+	}
+	if !canView {
+		return commentTarget{}, ErrNotAuthorized
+	}
+
+	if mediaID != nil {
+		media, err := s.MediaService.GetMediaByID(*mediaID)
+		if err != nil {
+			return commentTarget{}, err
+		}
+		if media.TripID != int64(trip.TripID) {
+			return commentTarget{}, fmt.Errorf("%w: media does not belong to this trip", ErrInvalidInput)
+		}
+		if !s.MediaService.CanViewMedia(media, int64(userID)) {
+			return commentTarget{}, ErrNotAuthorized
+		}
+	}
+
+	return commentTarget{trip: trip, mediaID: mediaID}, nil
+}
+
+func (s *CommentService) getCommentOnTarget(target commentTarget, commentID int64) (*models.Comment, error) {
+	comment, err := s.CommentRepo.GetCommentByID(commentID)
+	if err != nil {
+		return nil, err
+	}
+	sameMedia := comment.MediaID == nil && target.mediaID == nil ||
+		comment.MediaID != nil && target.mediaID != nil && *comment.MediaID == *target.mediaID
+	if comment.TripID != target.trip.TripID || !sameMedia {
+		return nil, fmt.Errorf("%w: comment does not belong to this trip or media", ErrInvalidInput)
+	}
+	return comment, nil
+}
+
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: comment cannot be empty", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(body) > models.MaxCommentLength {
+		return "", fmt.Errorf("%w: comment must be at most %d characters", ErrInvalidInput, models.MaxCommentLength)
+	}
+	return body, nil
+}
+
+// buildThreads nests the replies, which come oldest first, under the comments
+// they answer.
+func buildThreads(roots, replies []models.Comment) []models.Comment {
+	children := make(map[int64][]models.Comment)
+	for _, reply := range replies {
+		children[*reply.ParentID] = append(children[*reply.ParentID], reply)
+	}
+
+	var attach func(comment models.Comment) models.Comment
+	attach = func(comment models.Comment) models.Comment {
+		comment.Replies = []models.Comment{}
+		for _, child := range children[comment.CommentID] {
+			comment.Replies = append(comment.Replies, attach(child))
+		}
+		return comment
+	}
+
+	threads := make([]models.Comment, len(roots))
+	for i, root := range roots {
+		threads[i] = attach(root)
+	}
+	return threads
+}
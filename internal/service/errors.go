@@ -0,0 +1,13 @@
+package service
+
+import (
+	"errors"
+	"main/internal/db"
+)
+
+var (
+	ErrNotAuthorized = errors.New("not authorized")
+	ErrInvalidInput  = errors.New("invalid input")
+	// ErrVersionConflict means the resource changed since the client read it
+	ErrVersionConflict = db.ErrVersionConflict
+)
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bytes"
+	"main/internal/models"
+	"time"
+
+	"github.com/rwcarlsen/goexif/exif"
+	"github.com/rwcarlsen/goexif/tiff"
+)
+
+// EXIF 2.31 time zone tags, which goexif does not load by itself
+const (
+	OffsetTime         exif.FieldName = "OffsetTime"
+	OffsetTimeOriginal exif.FieldName = "OffsetTimeOriginal"
+)
+
+var offsetFields = map[uint16]exif.FieldName{
+	0x9010: OffsetTime,
+	0x9011: OffsetTimeOriginal,
+}
+
+func init() {
+	exif.RegisterParsers(offsetParser{})
+}
+
+// offsetParser loads the time zone tags from the Exif sub-IFD.
+type offsetParser struct{}
+
+func (offsetParser) Parse(x *exif.Exif) error {
+	tag, err := x.Get(exif.ExifIFDPointer)
+	if err != nil {
+		return nil
+	}
+	offset, err := tag.Int64(0)
+	if err != nil {
+		return nil
+	}
+	r := bytes.NewReader(x.Raw)
+	if _, err := r.Seek(offset, 0); err != nil {
+		return nil
+	}
+	dir, _, err := tiff.DecodeDir(r, x.Tiff.Order)
+	if err != nil {
+		return nil
+	}
+	x.LoadTags(dir, offsetFields, false)
+	return nil
+}
+
+// exifCaptureTime reads the photo's capture time. EXIF dates are wall clock
+// times, placed in time with OffsetTimeOriginal, OffsetTime or the camera's
+// own time zone. offsetKnown is false when none is present, and the wall
+// clock time is returned as UTC.
+func exifCaptureTime(x *exif.Exif) (captured time.Time, offsetKnown bool, err error) {
+	captured, err = x.DateTime()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	for _, field := range []exif.FieldName{OffsetTimeOriginal, OffsetTime} {
+		tag, err := x.Get(field)
+		if err != nil {
+			continue
+		}
+		value, err := tag.StringVal()
+		if err != nil {
+			continue
+		}
+		if loc, err := models.ParseUTCOffset(value); err == nil {
+			return models.WallClockIn(captured, loc), true, nil
+		}
+	}
+	if zone, _ := x.TimeZone(); zone != nil {
+		return captured.UTC(), true, nil
+	}
+	return models.WallClockIn(captured, time.UTC), false, nil
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"main/internal/db"
+	"main/internal/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ExpenseService struct {
+	ExpenseRepo *db.ExpensesRepository
+	TripService *TripService
+	// AdminIDs may update the exchange rates
+	AdminIDs []uint
+}
+
+// GetExpenses lists a trip's expenses to its members.
+func (s *ExpenseService) GetExpenses(tripID int, userID uint) ([]models.Expense, error) {
+	if _, err := s.memberTrip(tripID, userID); err != nil {
+		return nil, err
+	}
+	return s.ExpenseRepo.GetExpensesByTripID(tripID)
+}
+
+// CreateExpense records an expense paid by one participant and split equally
+// among others. Without a payer the caller paid, and without a split it is
+// shared by every participant.
+func (s *ExpenseService) CreateExpense(tripID int, userID uint, expense models.Expense) (*models.Expense, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canEdit, err := s.TripService.CanEditTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canEdit {
+		return nil, ErrNotAuthorized
+	}
+
+	if expense.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidInput)
+	}
+	currency, err := s.convertibleCurrency(expense.Currency)
+	if err != nil {
+		return nil, err
+	}
+	expense.Currency = currency
+	if !models.IsExpenseCategory(expense.Category) {
+		return nil, fmt.Errorf("%w: unknown expense category %q", ErrInvalidInput, expense.Category)
+	}
+	if expense.Date.IsZero() {
+		return nil, fmt.Errorf("%w: date is required", ErrInvalidInput)
+	}
+
+	participants, err := s.TripService.Participants(trip)
+	if err != nil {
+		return nil, err
+	}
+	if expense.PaidBy == 0 {
+		expense.PaidBy = userID
+	}
+	if !participants[expense.PaidBy] {
+		return nil, fmt.Errorf("%w: user %d is not part of this trip", ErrInvalidInput, expense.PaidBy)
+	}
+	if len(expense.SplitAmong) == 0 {
+		for participant := range participants {
+			expense.SplitAmong = append(expense.SplitAmong, participant)
+		}
+	}
+	seen := make(map[uint]bool)
+	var splitAmong []uint
+	for _, participant := range expense.SplitAmong {
+		if !participants[participant] {
+			return nil, fmt.Errorf("%w: user %d is not part of this trip", ErrInvalidInput, participant)
+		}
+		if !seen[participant] {
+			seen[participant] = true
+			splitAmong = append(splitAmong, participant)
+		}
+	}
+	sort.Slice(splitAmong, func(i, j int) bool { return splitAmong[i] < splitAmong[j] })
+	expense.SplitAmong = splitAmong
+
+	expense.TripID = tripID
+	expense.CreatedBy = userID
+	expense.CreatedAt = time.Now()
+	if err := s.ExpenseRepo.CreateExpense(&expense); err != nil {
+		return nil, fmt.Errorf("failed to create expense: %w", err)
+	}
+	return &expense, nil
+}
+
+func (s *ExpenseService) DeleteExpense(tripID int, expenseID int64, userID uint) error {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return err
+	}
+	canEdit, err := s.TripService.CanEditTrip(trip, userID)
+	if err != nil {
+		return err
+	}
+	if !canEdit {
+		return ErrNotAuthorized
+	}
+
+	expense, err := s.ExpenseRepo.GetExpenseByID(expenseID)
+	if err != nil {
+		return err
+	}
+	if expense.TripID != tripID {
+		return fmt.Errorf("%w: expense does not belong to this trip", ErrInvalidInput)
+	}
+
+	return s.ExpenseRepo.DeleteExpense(expenseID)
+}
+
+func (s *ExpenseService) SetBudget(tripID int, userID uint, amount models.Money, currency string) (*models.TripBudget, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canEdit, err := s.TripService.CanEditTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canEdit {
+		return nil, ErrNotAuthorized
+	}
+
+	if amount < 0 {
+		return nil, fmt.Errorf("%w: budget must not be negative", ErrInvalidInput)
+	}
+	currency, err = s.convertibleCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := &models.TripBudget{TripID: tripID, Amount: amount, Currency: currency}
+	if err := s.ExpenseRepo.SaveBudget(budget); err != nil {
+		return nil, fmt.Errorf("failed to save budget: %w", err)
+	}
+	return budget, nil
+}
+
+// GetSummary converts every expense to currency, which defaults to the
+// budget's currency and then to the base currency, and works out who owes
+// whom.
+func (s *ExpenseService) GetSummary(tripID int, userID uint, currency string) (*models.ExpenseSummary, error) {
+	trip, err := s.memberTrip(tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := s.ExpenseRepo.GetBudget(tripID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+	switch {
+	case currency != "":
+		if currency, err = models.NormalizeCurrency(currency); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+	case budget != nil:
+		currency = budget.Currency
+	default:
+		currency = models.BaseCurrency
+	}
+
+	rates, err := s.exchangeRates()
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.ExpenseRepo.GetExpensesByTripID(tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expenses: %w", err)
+	}
+	participants, err := s.TripService.Participants(trip)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.ExpenseSummary{
+		TripID:     tripID,
+		Currency:   currency,
+		ByCategory: make(map[string]models.Money),
+		ByDay:      []models.DailySpend{},
+		Balances:   []models.Balance{},
+		SettleUp:   []models.Settlement{},
+	}
+	byDay := make(map[string]*models.DailySpend)
+	paid := make(map[uint]models.Money)
+	share := make(map[uint]models.Money)
+	for participant := range participants {
+		paid[participant] += 0
+	}
+
+	for _, expense := range expenses {
+		amount, err := rates.Convert(expense.Amount, expense.Currency, currency)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+
+		summary.Total += amount
+		summary.ByCategory[expense.Category] += amount
+		day, ok := byDay[expense.Date.String()]
+		if !ok {
+			day = &models.DailySpend{Date: expense.Date}
+			byDay[expense.Date.String()] = day
+		}
+		day.Total += amount
+
+		paid[expense.PaidBy] += amount
+		if len(expense.SplitAmong) == 0 {
+			expense.SplitAmong = []uint{expense.PaidBy}
+		}
+		// Equal shares, the leftover cents go to the first participants
+		n := models.Money(len(expense.SplitAmong))
+		for i, participant := range expense.SplitAmong {
+			portion := amount / n
+			if models.Money(i) < amount%n {
+				portion++
+			}
+			share[participant] += portion
+			paid[participant] += 0
+		}
+	}
+
+	for _, day := range byDay {
+		summary.ByDay = append(summary.ByDay, *day)
+	}
+	sort.Slice(summary.ByDay, func(i, j int) bool { return summary.ByDay[i].Date.Before(summary.ByDay[j].Date.Time) })
+
+	for participant := range paid {
+		summary.Balances = append(summary.Balances, models.Balance{
+			UserID:  participant,
+			Paid:    paid[participant],
+			Share:   share[participant],
+			Balance: paid[participant] - share[participant],
+		})
+	}
+	sort.Slice(summary.Balances, func(i, j int) bool { return summary.Balances[i].UserID < summary.Balances[j].UserID })
+	summary.SettleUp = settleUp(summary.Balances)
+
+	if budget != nil {
+		budgetAmount, err := rates.Convert(budget.Amount, budget.Currency, currency)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		remaining := budgetAmount - summary.Total
+		summary.Budget = &budgetAmount
+		summary.Remaining = &remaining
+	}
+
+	return summary, nil
+}
+
+// settleUp pays back the largest creditor from the largest debtor until every
+// balance is zero, which keeps the number of transfers low.
+func settleUp(balances []models.Balance) []models.Settlement {
+	type entry struct {
+		userID uint
+		amount models.Money
+	}
+	var creditors, debtors []entry
+	for _, balance := range balances {
+		switch {
+		case balance.Balance > 0:
+			creditors = append(creditors, entry{balance.UserID, balance.Balance})
+		case balance.Balance < 0:
+			debtors = append(debtors, entry{balance.UserID, -balance.Balance})
+		}
+	}
+	byAmount := func(entries []entry) func(i, j int) bool {
+		return func(i, j int) bool {
+			if entries[i].amount == entries[j].amount {
+				return entries[i].userID < entries[j].userID
+			}
+			return entries[i].amount > entries[j].amount
+		}
+	}
+	sort.Slice(creditors, byAmount(creditors))
+	sort.Slice(debtors, byAmount(debtors))
+
+	settlements := []models.Settlement{}
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		amount := min(debtors[i].amount, creditors[j].amount)
+		settlements = append(settlements, models.Settlement{From: debtors[i].userID, To: creditors[j].userID, Amount: amount})
+		debtors[i].amount -= amount
+		creditors[j].amount -= amount
+		if debtors[i].amount == 0 {
+			i++
+		}
+		if creditors[j].amount == 0 {
+			j++
+		}
+	}
+	return settlements
+}
+
+func (s *ExpenseService) GetExchangeRates() ([]models.ExchangeRate, error) {
+	return s.ExpenseRepo.GetExchangeRates()
+}
+
+// SetExchangeRate stores how many units of currency one base currency buys.
+func (s *ExpenseService) SetExchangeRate(currency string, rate float64, userID uint) (*models.ExchangeRate, error) {
+	if !s.isAdmin(userID) {
+		return nil, ErrNotAuthorized
+	}
+
+	currency, err := models.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if currency == models.BaseCurrency {
+		return nil, fmt.Errorf("%w: %s is the base currency", ErrInvalidInput, models.BaseCurrency)
+	}
+	if rate <= 0 {
+		return nil, fmt.Errorf("%w: rate must be positive", ErrInvalidInput)
+	}
+
+	exchangeRate := &models.ExchangeRate{
+		Currency:  currency,
+		Rate:      rate,
+		UpdatedBy: userID,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.ExpenseRepo.SaveExchangeRate(exchangeRate); err != nil {
+		return nil, fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+	return exchangeRate, nil
+}
+
+func (s *ExpenseService) exchangeRates() (models.ExchangeRates, error) {
+	stored, err := s.ExpenseRepo.GetExchangeRates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+	rates := make(models.ExchangeRates)
+	for _, rate := range stored {
+		rates[rate.Currency] = rate.Rate
+	}
+	return rates, nil
+}
+
+// convertibleCurrency normalizes currency and makes sure an exchange rate is
+// stored for it, so summaries can always convert amounts in it.
+func (s *ExpenseService) convertibleCurrency(currency string) (string, error) {
+	currency, err := models.NormalizeCurrency(currency)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	rates, err := s.exchangeRates()
+	if err != nil {
+		return "", err
+	}
+	if !rates.Has(currency) {
+		return "", fmt.Errorf("%w: no exchange rate for %s", ErrInvalidInput, currency)
+	}
+	return currency, nil
+}
+
+// memberTrip returns the trip when the user is one of its members.
+func (s *ExpenseService) memberTrip(tripID int, userID uint) (models.Trip, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	role, err := s.TripService.GetUserRole(trip, userID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	if role == "" {
+		return models.Trip{}, ErrNotAuthorized
+	}
+	return trip, nil
+}
+
+func (s *ExpenseService) isAdmin(userID uint) bool {
+	for _, adminID := range s.AdminIDs {
+		if adminID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"main/internal/db"
+	"main/internal/models"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// maxImportPhotoSize caps the photos of an import, they are read in
+	// memory for their EXIF data
+	maxImportPhotoSize = 200 << 20
+	// maxImportJSONSize caps manifests and Takeout sidecars
+	maxImportJSONSize = 16 << 20
+	// importHeartbeatInterval is how often a running import renews its lease
+	importHeartbeatInterval = 30 * time.Second
+	// importLeaseTimeout is how long an import can go without a heartbeat
+	// before it is taken for interrupted
+	importLeaseTimeout = 3 * importHeartbeatInterval
+)
+
+type ImportService struct {
+	ImportRepo   *db.ImportJobsRepository
+	TripService  *TripService
+	MediaService *MediaService
+}
+
+// importPlan is what an archive holds, worked out when the job starts.
+type importPlan struct {
+	source   models.ImportSource
+	manifest *models.TripArchiveManifest
+	files    map[string]*zip.File
+	albums   []takeoutAlbum
+	items    int
+}
+
+// StartImport checks that the file is a ZIP archive, records the job and
+// imports the archive in the background. Reading its manifest or sidecars is
+// left to the job, which fails if the archive turns out to be unusable. On
+// success the job owns the archive file and removes it once done; otherwise
+// the caller does.
+func (s *ImportService) StartImport(userID uint, archivePath string, visibility models.VisibilityEnum) (*models.ImportJob, error) {
+	switch visibility {
+	case "":
+		visibility = models.Private
+	case models.Public, models.Friends, models.Private:
+	default:
+		return nil, fmt.Errorf("%w: invalid visibility %q", ErrInvalidInput, visibility)
+	}
+
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a ZIP archive", ErrInvalidInput)
+	}
+
+	job := &models.ImportJob{
+		UserID:  userID,
+		Source:  archiveSource(&archive.Reader),
+		Status:  models.ImportPending,
+		TripIDs: models.TripIDList{},
+	}
+	if err := s.ImportRepo.CreateJob(job); err != nil {
+		archive.Close()
+		if errors.Is(err, db.ErrImportInProgress) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	// The job is updated by the import from now on
+	created := *job
+	go s.run(job, archive, archivePath, visibility)
+	return &created, nil
+}
+
+func (s *ImportService) GetJob(jobID int64, userID uint) (*models.ImportJob, error) {
+	job, err := s.ImportRepo.GetJobByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.UserID != userID {
+		return nil, ErrNotAuthorized
+	}
+	return job, nil
+}
+
+func (s *ImportService) GetJobs(userID uint) ([]models.ImportJob, error) {
+	jobs, err := s.ImportRepo.GetJobsByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// FailInterruptedJobs fails the unfinished imports whose lease has expired:
+// the process running them is gone, and their archives with it. Imports
+// running on other replicas keep renewing their lease.
+func (s *ImportService) FailInterruptedJobs() error {
+	return s.ImportRepo.FailStaleJobs(time.Now().Add(-importLeaseTimeout),
+		"interrupted by a server restart, please upload the archive again")
+}
+
+// RunInterruptedJobsCheck runs FailInterruptedJobs every interval until the
+// process exits.
+func (s *ImportService) RunInterruptedJobsCheck(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.FailInterruptedJobs(); err != nil {
+			fmt.Printf("Error: Failed to mark interrupted imports as failed - %v\n", err)
+		}
+		<-ticker.C
+	}
+}
+
+// heartbeat renews the job's lease until done is closed.
+func (s *ImportService) heartbeat(jobID int64, done <-chan struct{}) {
+	ticker := time.NewTicker(importHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := s.ImportRepo.Heartbeat(jobID); err != nil {
+				fmt.Printf("Error: Failed to renew the lease of import job %d - %v\n", jobID, err)
+			}
+		}
+	}
+}
+
+func (s *ImportService) run(job *models.ImportJob, archive *zip.ReadCloser, archivePath string, visibility models.VisibilityEnum) {
+	defer os.Remove(archivePath)
+	defer archive.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.heartbeat(job.JobID, done)
+
+	var err error
+	defer func() {
+		// Decoding arbitrary files must not take the server down
+		if r := recover(); r != nil {
+			err = fmt.Errorf("import aborted: %v", r)
+		}
+		finished := time.Now()
+		job.FinishedAt = &finished
+		job.Status = models.ImportCompleted
+		if err != nil {
+			fmt.Printf("Error: Import job %d failed - %v\n", job.JobID, err)
+			job.Status = models.ImportFailed
+			job.Error = err.Error()
+		}
+		s.saveProgress(job)
+	}()
+
+	job.Status = models.ImportRunning
+	s.saveProgress(job)
+
+	var plan *importPlan
+	plan, err = planImport(&archive.Reader)
+	if err != nil {
+		return
+	}
+	job.Source = plan.source
+	job.TotalItems = plan.items
+	s.saveProgress(job)
+
+	switch plan.source {
+	case models.ImportNostos:
+		err = s.importNostos(job, plan)
+	case models.ImportTakeout:
+		for _, album := range plan.albums {
+			s.importTakeoutAlbum(job, album, visibility)
+		}
+	}
+}
+
+func (s *ImportService) saveProgress(job *models.ImportJob) {
+	err := s.ImportRepo.UpdateProgress(job)
+	if errors.Is(err, db.ErrImportJobFinished) {
+		fmt.Printf("Error: Import job %d was marked as finished elsewhere, its progress is not saved\n", job.JobID)
+	} else if err != nil {
+		fmt.Printf("Error: Failed to save the progress of import job %d - %v\n", job.JobID, err)
+	}
+}
+
+func (s *ImportService) itemDone(job *models.ImportJob, name string, err error) {
+	job.ProcessedItems++
+	if err != nil {
+		fmt.Printf("Error: Import job %d failed to import %s - %v\n", job.JobID, name, err)
+		job.FailedItems++
+	}
+	s.saveProgress(job)
+}
+
+// archiveSource guesses the kind of archive from its file names alone, for
+// the job to show until planImport has read it.
+func archiveSource(archive *zip.Reader) models.ImportSource {
+	for _, file := range archive.File {
+		if file.Name == models.ArchiveManifestName {
+			return models.ImportNostos
+		}
+	}
+	return models.ImportTakeout
+}
+
+func planImport(archive *zip.Reader) (*importPlan, error) {
+	plan := &importPlan{files: make(map[string]*zip.File)}
+	for _, file := range archive.File {
+		plan.files[file.Name] = file
+	}
+
+	if manifestFile, ok := plan.files[models.ArchiveManifestName]; ok {
+		var manifest models.TripArchiveManifest
+		if err := readJSON(manifestFile, &manifest); err != nil {
+			return nil, fmt.Errorf("%w: invalid %s: %v", ErrInvalidInput, models.ArchiveManifestName, err)
+		}
+		if strings.TrimSpace(manifest.Trip.Name) == "" {
+			return nil, fmt.Errorf("%w: the archived trip has no name", ErrInvalidInput)
+		}
+		plan.source = models.ImportNostos
+		plan.manifest = &manifest
+		for _, m := range manifest.Media {
+			if !m.Missing && plan.files[m.File] != nil {
+				plan.items++
+			}
+		}
+		return plan, nil
+	}
+
+	albums, err := planTakeout(archive.File)
+	if err != nil {
+		return nil, err
+	}
+	if albums == nil {
+		return nil, fmt.Errorf("%w: expected a trip archive or a Google Takeout export of Google Photos", ErrInvalidInput)
+	}
+	plan.source = models.ImportTakeout
+	plan.albums = albums
+	for _, album := range albums {
+		plan.items += len(album.items)
+	}
+	if plan.items == 0 {
+		return nil, fmt.Errorf("%w: the archive has no photos or videos", ErrInvalidInput)
+	}
+	return plan, nil
+}
+
+// importNostos recreates an archived trip and its media.
+func (s *ImportService) importNostos(job *models.ImportJob, plan *importPlan) error {
+	archived := plan.manifest.Trip
+	trip := models.Trip{
+		UserID:      job.UserID,
+		Name:        archived.Name,
+		Description: archived.Description,
+		Visibility:  archived.Visibility,
+		StartDate:   archived.StartDate,
+		EndDate:     archived.EndDate,
+		Language:    archived.Language,
+		Category:    archived.Category,
+		Tags:        archived.Tags,
+	}
+	if trip.Visibility == "" {
+		trip.Visibility = string(models.Private)
+	}
+	created, err := s.TripService.CreateTrip(trip)
+	if err != nil {
+		return fmt.Errorf("failed to create trip: %w", err)
+	}
+	trip = created.(models.Trip)
+	job.TripIDs = append(job.TripIDs, trip.TripID)
+	s.saveProgress(job)
+
+	// Location IDs of the archive are those of the instance it comes from
+	archivedLocations := make(map[int64]models.Location)
+	for _, location := range plan.manifest.Locations {
+		archivedLocations[location.LocationID] = location
+	}
+	locationIDs := make(map[int64]int64)
+
+	for _, m := range plan.manifest.Media {
+		file := plan.files[m.File]
+		if m.Missing || file == nil {
+			continue
+		}
+
+		if _, ok := locationIDs[m.LocationID]; !ok {
+			locationIDs[m.LocationID] = s.localLocationID(archivedLocations[m.LocationID])
+		}
+		media := models.Media{
+			TripID:       int64(trip.TripID),
+			UserID:       int64(job.UserID),
+			LocationID:   locationIDs[m.LocationID],
+			Type:         m.Type,
+			Visibility:   m.Visibility,
+			UploadDate:   time.Now(),
+			CaptureDate:  m.CaptureDate,
+			GpsLatitude:  m.GpsLatitude,
+			GpsLongitude: m.GpsLongitude,
+			GpsAltitude:  m.GpsAltitude,
+		}
+		if media.Visibility == "" {
+			media.Visibility = models.VisibilityEnum(trip.Visibility)
+		}
+		s.itemDone(job, m.File, s.importFile(file, originalName(m.FilePath), &media))
+	}
+	return nil
+}
+
+// importFile uploads the archived file and saves the media pointing to it.
+func (s *ImportService) importFile(file *zip.File, name string, media *models.Media) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	objectName, err := s.MediaService.MinioService.UploadObject(name, reader, int64(file.UncompressedSize64), contentTypeOf(name, nil))
+	if err != nil {
+		return fmt.Errorf("failed to upload: %w", err)
+	}
+	media.FilePath = objectName
+	return s.saveImportedMedia(media)
+}
+
+// saveImportedMedia removes the uploaded object when the media cannot be saved.
+func (s *ImportService) saveImportedMedia(media *models.Media) error {
+	if err := s.MediaService.SaveMedia(media); err != nil {
+		if err := s.MediaService.MinioService.DeleteObject(media.FilePath); err != nil {
+			fmt.Printf("Error: Failed to remove object %s - %v\n", media.FilePath, err)
+		}
+		return fmt.Errorf("failed to save media: %w", err)
+	}
+	return nil
+}
+
+// localLocationID finds or creates the location of this instance matching an
+// archived one. Zero means no location.
+func (s *ImportService) localLocationID(archived models.Location) int64 {
+	if archived.City == "" && archived.Country == "" {
+		return 0
+	}
+	location := archived
+	location.LocationID = 0
+	if found, err := s.MediaService.GetLocationByCountryAndCity(&location); err == nil {
+		return found.LocationID
+	}
+	created, err := s.MediaService.setLocationInfo(&location)
+	if err != nil {
+		fmt.Printf("Error: Failed to save location %s - %v\n", location.Name, err)
+		return 0
+	}
+	return created.LocationID
+}
+
+func readJSON(file *zip.File, v any) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return json.NewDecoder(io.LimitReader(reader, maxImportJSONSize)).Decode(v)
+}
+
+var objectNamePrefix = regexp.MustCompile(`^\d+_`)
+
+// originalName strips the upload timestamp MinioService prefixes object
+// names with.
+func originalName(objectName string) string {
+	return objectNamePrefix.ReplaceAllString(path.Base(objectName), "")
+}
+
+func contentTypeOf(name string, head []byte) string {
+	if contentType := mime.TypeByExtension(strings.ToLower(path.Ext(name))); contentType != "" {
+		return contentType
+	}
+	if len(head) > 0 {
+		return http.DetectContentType(head)
+	}
+	return "application/octet-stream"
+}
+
+// memoryFile lets in-memory files go through ExtractMetadata.
+type memoryFile struct {
+	*bytes.Reader
+}
+
+func (memoryFile) Close() error {
+	return nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"fmt"
+	"main/internal/db"
+	"main/internal/models"
+)
+
+type ItineraryService struct {
+	ItineraryRepo *db.ItineraryRepository
+	TripService   *TripService
+}
+
+func (s *ItineraryService) GetItinerary(tripID int, userID uint) ([]models.ItineraryDay, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.TripService.CanViewTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+
+	days, err := s.ItineraryRepo.GetDaysByTripID(tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get itinerary days: %w", err)
+	}
+
+	stops, err := s.ItineraryRepo.GetStopsByTripID(tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get itinerary stops: %w", err)
+	}
+
+	// Resolve the linked locations in a single query
+	var locationIDs []int64
+	for _, stop := range stops {
+		if stop.LocationID != nil {
+			locationIDs = append(locationIDs, *stop.LocationID)
+		}
+	}
+	locations, err := s.ItineraryRepo.GetLocationsByIDs(locationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stop locations: %w", err)
+	}
+	locationsByID := make(map[int64]models.Location)
+	for _, location := range locations {
+		locationsByID[location.LocationID] = location
+	}
+
+	stopsByDay := make(map[int64][]models.ItineraryStop)
+	for _, stop := range stops {
+		if stop.LocationID != nil {
+			if location, ok := locationsByID[*stop.LocationID]; ok {
+				stop.Location = &location
+			}
+		}
+		stopsByDay[stop.DayID] = append(stopsByDay[stop.DayID], stop)
+	}
+
+	for i := range days {
+		days[i].Stops = stopsByDay[days[i].DayID]
+		if days[i].Stops == nil {
+			days[i].Stops = []models.ItineraryStop{}
+		}
+	}
+
+	return days, nil
+}
+
+func (s *ItineraryService) CreateDay(tripID int, userID uint, day models.ItineraryDay) (*models.ItineraryDay, error) {
+	if err := s.checkEditable(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	if day.Date.IsZero() {
+		return nil, fmt.Errorf("%w: date is required", ErrInvalidInput)
+	}
+
+	day.TripID = tripID
+	if err := s.ItineraryRepo.CreateDay(&day); err != nil {
+		return nil, fmt.Errorf("failed to create itinerary day: %w", err)
+	}
+	day.Stops = []models.ItineraryStop{}
+
+	return &day, nil
+}
+
+func (s *ItineraryService) DeleteDay(tripID int, dayID int64, userID uint) error {
+	if err := s.checkEditable(tripID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.getDayInTrip(tripID, dayID); err != nil {
+		return err
+	}
+
+	return s.ItineraryRepo.DeleteDay(dayID)
+}
+
+func (s *ItineraryService) CreateStop(tripID int, dayID int64, userID uint, stop models.ItineraryStop) (*models.ItineraryStop, error) {
+	if err := s.checkEditable(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getDayInTrip(tripID, dayID); err != nil {
+		return nil, err
+	}
+
+	if stop.Name == "" {
+		return nil, fmt.Errorf("%w: stop name is required", ErrInvalidInput)
+	}
+	if stop.PlannedStart != nil && stop.PlannedEnd != nil && stop.PlannedEnd.Before(*stop.PlannedStart) {
+		return nil, fmt.Errorf("%w: planned_end must not be before planned_start", ErrInvalidInput)
+	}
+
+	if stop.LocationID != nil {
+		locations, err := s.ItineraryRepo.GetLocationsByIDs([]int64{*stop.LocationID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get location: %w", err)
+		}
+		if len(locations) == 0 {
+			return nil, fmt.Errorf("%w: location %d does not exist", ErrInvalidInput, *stop.LocationID)
+		}
+		stop.Location = &locations[0]
+	}
+
+	stop.TripID = tripID
+	stop.DayID = dayID
+	if err := s.ItineraryRepo.CreateStop(&stop); err != nil {
+		return nil, fmt.Errorf("failed to create itinerary stop: %w", err)
+	}
+
+	return &stop, nil
+}
+
+func (s *ItineraryService) DeleteStop(tripID int, stopID int64, userID uint) error {
+	if err := s.checkEditable(tripID, userID); err != nil {
+		return err
+	}
+
+	stop, err := s.ItineraryRepo.GetStopByID(stopID)
+	if err != nil {
+		return err
+	}
+	if stop.TripID != tripID {
+		return fmt.Errorf("%w: stop does not belong to this trip", ErrInvalidInput)
+	}
+
+	return s.ItineraryRepo.DeleteStop(stopID)
+}
+
+// ReorderStops expects stopIDs to contain every stop of the day exactly once.
+func (s *ItineraryService) ReorderStops(tripID int, dayID int64, userID uint, stopIDs []int64) ([]models.ItineraryStop, error) {
+	if err := s.checkEditable(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getDayInTrip(tripID, dayID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.ItineraryRepo.GetStopsByDayID(dayID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get itinerary stops: %w", err)
+	}
+
+	if len(current) != len(stopIDs) {
+		return nil, fmt.Errorf("%w: expected %d stop IDs, got %d", ErrInvalidInput, len(current), len(stopIDs))
+	}
+	remaining := make(map[int64]bool)
+	for _, stop := range current {
+		remaining[stop.StopID] = true
+	}
+	for _, stopID := range stopIDs {
+		if !remaining[stopID] {
+			return nil, fmt.Errorf("%w: stop %d is missing, duplicated or not part of this day", ErrInvalidInput, stopID)
+		}
+		delete(remaining, stopID)
+	}
+
+	if err := s.ItineraryRepo.ReorderStops(dayID, stopIDs); err != nil {
+		return nil, fmt.Errorf("failed to reorder stops: %w", err)
+	}
+
+	return s.ItineraryRepo.GetStopsByDayID(dayID)
+}
+
+func (s *ItineraryService) checkEditable(tripID int, userID uint) error {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return err
+	}
+	canEdit, err := s.TripService.CanEditTrip(trip, userID)
+	if err != nil {
+		return err
+	}
+	if !canEdit {
+		return ErrNotAuthorized
+	}
+	return nil
+}
+
+func (s *ItineraryService) getDayInTrip(tripID int, dayID int64) (*models.ItineraryDay, error) {
+	day, err := s.ItineraryRepo.GetDayByID(dayID)
+	if err != nil {
+		return nil, err
+	}
+	if day.TripID != tripID {
+		return nil, fmt.Errorf("%w: day does not belong to this trip", ErrInvalidInput)
+	}
+	return day, nil
+}
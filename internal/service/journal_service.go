@@ -0,0 +1,337 @@
+package service
+
+import (
+	"fmt"
+	"main/internal/db"
+	"main/internal/models"
+	"strings"
+	"time"
+)
+
+type JournalService struct {
+	JournalRepo  *db.JournalRepository
+	TripService  *TripService
+	MediaService *MediaService
+}
+
+// GetEntries lists the entries of a trip the user can see, optionally only
+// those of one day.
+func (s *JournalService) GetEntries(tripID int, userID uint, date *models.Date) ([]models.JournalEntry, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.TripService.CanViewTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+
+	entries, err := s.JournalRepo.GetEntriesByTripID(tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal entries: %w", err)
+	}
+
+	visible := []models.JournalEntry{}
+	for _, entry := range entries {
+		if date != nil && entry.Date.String() != date.String() {
+			continue
+		}
+		canView, err := s.canViewEntry(trip, entry, userID)
+		if err != nil {
+			return nil, err
+		}
+		if canView {
+			visible = append(visible, entry)
+		}
+	}
+
+	if err := s.withDetails(visible, userID); err != nil {
+		return nil, err
+	}
+	return visible, nil
+}
+
+func (s *JournalService) GetEntry(tripID int, entryID int64, userID uint) (*models.JournalEntry, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.TripService.CanViewTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+
+	entry, err := s.getEntryInTrip(tripID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err = s.canViewEntry(trip, *entry, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+
+	entries := []models.JournalEntry{*entry}
+	if err := s.withDetails(entries, userID); err != nil {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// CreateEntry lets owners and editors write an entry. Its visibility
+// defaults to the trip's and can only be narrower.
+func (s *JournalService) CreateEntry(tripID int, userID uint, entry models.JournalEntry) (*models.JournalEntry, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canEdit, err := s.TripService.CanEditTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canEdit {
+		return nil, ErrNotAuthorized
+	}
+
+	if err := s.prepareEntry(trip, &entry); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry.TripID = tripID
+	entry.AuthorID = userID
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+	if err := s.JournalRepo.CreateEntry(&entry); err != nil {
+		return nil, fmt.Errorf("failed to create journal entry: %w", err)
+	}
+
+	entries := []models.JournalEntry{entry}
+	if err := s.withDetails(entries, userID); err != nil {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// UpdateEntry replaces an entry's content. Only its author and the trip's
+// owners may change it, as long as they can still edit the trip.
+func (s *JournalService) UpdateEntry(tripID int, entryID int64, userID uint, update models.JournalEntry) (*models.JournalEntry, error) {
+	trip, existing, err := s.editableEntry(tripID, entryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prepareEntry(trip, &update); err != nil {
+		return nil, err
+	}
+
+	update.EntryID = existing.EntryID
+	update.TripID = existing.TripID
+	update.AuthorID = existing.AuthorID
+	update.CreatedAt = existing.CreatedAt
+	update.UpdatedAt = time.Now()
+	if err := s.JournalRepo.UpdateEntry(&update); err != nil {
+		return nil, fmt.Errorf("failed to update journal entry: %w", err)
+	}
+
+	entries := []models.JournalEntry{update}
+	if err := s.withDetails(entries, userID); err != nil {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+func (s *JournalService) DeleteEntry(tripID int, entryID int64, userID uint) error {
+	if _, _, err := s.editableEntry(tripID, entryID, userID); err != nil {
+		return err
+	}
+	return s.JournalRepo.DeleteEntry(entryID)
+}
+
+// prepareEntry validates the entry against its trip and collects the media
+// its body references.
+func (s *JournalService) prepareEntry(trip models.Trip, entry *models.JournalEntry) error {
+	if entry.Date.IsZero() {
+		return fmt.Errorf("%w: date is required", ErrInvalidInput)
+	}
+	if trip.StartDate != nil && entry.Date.Before(trip.StartDate.Time) ||
+		trip.EndDate != nil && entry.Date.After(trip.EndDate.Time) {
+		return fmt.Errorf("%w: date must be within the trip's dates", ErrInvalidInput)
+	}
+
+	entry.Title = strings.TrimSpace(entry.Title)
+	if len(entry.Body) > models.MaxJournalBodyLength {
+		return fmt.Errorf("%w: body must be at most %d bytes", ErrInvalidInput, models.MaxJournalBodyLength)
+	}
+	entry.Body = strings.TrimSpace(strings.ReplaceAll(entry.Body, "\r\n", "\n"))
+	if entry.Body == "" {
+		return fmt.Errorf("%w: body is required", ErrInvalidInput)
+	}
+
+	tripVisibility := models.VisibilityEnum(trip.Visibility)
+	if entry.Visibility == "" {
+		entry.Visibility = tripVisibility
+	}
+	switch entry.Visibility {
+	case models.Public, models.Friends, models.Private:
+	default:
+		return fmt.Errorf("%w: visibility must be PUBLIC, FRIENDS or PRIVATE", ErrInvalidInput)
+	}
+	if !tripVisibility.AtLeastAsOpenAs(entry.Visibility) {
+		return fmt.Errorf("%w: an entry of a %s trip cannot be %s", ErrInvalidInput, trip.Visibility, entry.Visibility)
+	}
+
+	if entry.LocationID != nil {
+		locations, err := s.MediaService.MediaRepo.GetLocationsByIDs([]int64{*entry.LocationID})
+		if err != nil {
+			return fmt.Errorf("failed to get location: %w", err)
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("%w: location %d does not exist", ErrInvalidInput, *entry.LocationID)
+		}
+	}
+
+	entry.MediaIDs = models.MediaReferences(entry.Body)
+	media, err := s.MediaService.MediaRepo.GetMediaByIDs(entry.MediaIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get media: %w", err)
+	}
+	inTrip := make(map[int64]bool)
+	for _, m := range media {
+		if m.TripID == int64(trip.TripID) {
+			inTrip[m.MediaID] = true
+		}
+	}
+	for _, mediaID := range entry.MediaIDs {
+		if !inTrip[mediaID] {
+			return fmt.Errorf("%w: media %d does not belong to this trip", ErrInvalidInput, mediaID)
+		}
+	}
+
+	return nil
+}
+
+// canViewEntry applies the entry's own visibility on top of the trip's,
+// which the caller has already checked. Members see every entry.
+func (s *JournalService) canViewEntry(trip models.Trip, entry models.JournalEntry, userID uint) (bool, error) {
+	role, err := s.TripService.GetUserRole(trip, userID)
+	if err != nil {
+		return false, err
+	}
+	if role != "" {
+		return true, nil
+	}
+	switch entry.Visibility {
+	case models.Public:
+		return true, nil
+	case models.Friends:
+		return s.TripService.AreFriends(trip.UserID, userID), nil
+	}
+	return false, nil
+}
+
+func (s *JournalService) editableEntry(tripID int, entryID int64, userID uint) (models.Trip, *models.JournalEntry, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return models.Trip{}, nil, err
+	}
+	role, err := s.TripService.GetUserRole(trip, userID)
+	if err != nil {
+		return models.Trip{}, nil, err
+	}
+	if !role.CanEdit() {
+		return models.Trip{}, nil, ErrNotAuthorized
+	}
+
+	entry, err := s.getEntryInTrip(tripID, entryID)
+	if err != nil {
+		return models.Trip{}, nil, err
+	}
+	if entry.AuthorID != userID && role != models.RoleOwner {
+		return models.Trip{}, nil, ErrNotAuthorized
+	}
+	return trip, entry, nil
+}
+
+func (s *JournalService) getEntryInTrip(tripID int, entryID int64) (*models.JournalEntry, error) {
+	entry, err := s.JournalRepo.GetEntryByID(entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.TripID != tripID {
+		return nil, fmt.Errorf("%w: entry does not belong to this trip", ErrInvalidInput)
+	}
+	return entry, nil
+}
+
+// withDetails renders the entries' bodies and resolves their locations and the
+// referenced media the user may see, with presigned URLs. Hidden or deleted
+// media are left out.
+func (s *JournalService) withDetails(entries []models.JournalEntry, userID uint) error {
+	var mediaIDs, locationIDs []int64
+	for _, entry := range entries {
+		mediaIDs = append(mediaIDs, entry.MediaIDs...)
+		if entry.LocationID != nil {
+			locationIDs = append(locationIDs, *entry.LocationID)
+		}
+	}
+
+	media, err := s.MediaService.MediaRepo.GetMediaByIDs(mediaIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get media: %w", err)
+	}
+	mediaList := make([]*models.Media, len(media))
+	for i := range media {
+		mediaList[i] = &media[i]
+	}
+	mediaByID := make(map[int64]models.MediaByTrip)
+	for _, m := range s.MediaService.filterVisibleMedia(mediaList, int64(userID)) {
+		url, err := s.MediaService.MinioService.GetPresignedURL(m.FilePath, time.Minute*5)
+		if err != nil {
+			continue
+		}
+		mediaByID[m.MediaID] = models.MediaByTrip{
+			MediaID:   m.MediaID,
+			URL:       url,
+			Latitude:  m.GpsLatitude,
+			Longitude: m.GpsLongitude,
+		}
+	}
+
+	locations, err := s.MediaService.MediaRepo.GetLocationsByIDs(locationIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get locations: %w", err)
+	}
+	locationsByID := make(map[int64]models.Location)
+	for _, location := range locations {
+		locationsByID[location.LocationID] = location
+	}
+
+	for i := range entries {
+		bodyHTML, err := models.RenderMarkdown(entries[i].Body)
+		if err != nil {
+			return fmt.Errorf("failed to render entry %d: %w", entries[i].EntryID, err)
+		}
+		entries[i].BodyHTML = bodyHTML
+		entries[i].Media = []models.MediaByTrip{}
+		for _, mediaID := range entries[i].MediaIDs {
+			if m, ok := mediaByID[mediaID]; ok {
+				entries[i].Media = append(entries[i].Media, m)
+			}
+		}
+		if entries[i].LocationID != nil {
+			if location, ok := locationsByID[*entries[i].LocationID]; ok {
+				entries[i].Location = &location
+			}
+		}
+	}
+	return nil
+}
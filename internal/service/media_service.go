@@ -35,10 +35,7 @@ func (s *MediaService) GetMediaByTripID(tripID int64, userID int64) ([]models.Me
 	var response []models.MediaByTrip
 	for _, media := range mediaList {
 		// Check visibility permissions
-		if media.Visibility == "PRIVATE" && media.UserID != userID {
-			continue
-		}
-		if media.Visibility == "FRIENDS" && !s.MediaRepo.AreFriends(userID, media.UserID) {
+		if !s.CanViewMedia(media, userID) {
 			continue
 		}
 
@@ -66,31 +63,62 @@ func (s *MediaService) GetMediaDataByTripID(tripID int64, userID int64) ([]model
 		return nil, fmt.Errorf("failed to get media: %w", err)
 	}
 
+	return s.filterVisibleMedia(mediaList, userID), nil
+}
+
+// GetMediaDataByTripIDs is GetMediaDataByTripID for several trips at once.
+func (s *MediaService) GetMediaDataByTripIDs(tripIDs []int, userID int64) ([]models.Media, error) {
+	mediaList, err := s.MediaRepo.GetMediaByTripIDs(tripIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+
+	return s.filterVisibleMedia(mediaList, userID), nil
+}
+
+func (s *MediaService) filterVisibleMedia(mediaList []*models.Media, userID int64) []models.Media {
 	// Filter media based on visibility permissions
 	var filteredMedia []models.Media
 	for _, media := range mediaList {
-		// Check visibility permissions
-		if media.Visibility == "PRIVATE" && media.UserID != userID {
-			continue
-		}
-		if media.Visibility == "FRIENDS" && !s.MediaRepo.AreFriends(userID, media.UserID) {
+		if !s.CanViewMedia(media, userID) {
 			continue
 		}
 		filteredMedia = append(filteredMedia, *media) // Dereference the pointer
 	}
 
-	return filteredMedia, nil
+	return filteredMedia
+}
+
+// CanViewMedia applies the media's visibility: PRIVATE media are only seen by
+// their uploader and FRIENDS media by the uploader's friends.
+func (s *MediaService) CanViewMedia(media *models.Media, userID int64) bool {
+	return canViewMedia(s.MediaRepo, media, userID)
+}
+
+func canViewMedia(repo *db.MediaRepository, media *models.Media, userID int64) bool {
+	switch media.Visibility {
+	case models.Private:
+		return media.UserID == userID
+	case models.Friends:
+		return media.UserID == userID || repo.AreFriends(userID, media.UserID)
+	}
+	return true
 }
 
-func (s *MediaService) ChangeMediaVisibility(mediaID int64, i int64, visibility models.VisibilityEnum) error {
+// ChangeMediaVisibility fails with ErrVersionConflict when version is set and
+// the media has changed since.
+func (s *MediaService) ChangeMediaVisibility(mediaID int64, i int64, visibility models.VisibilityEnum, version int) error {
 	media, err := s.MediaRepo.GetMediaByID(mediaID)
 	if media == nil || err != nil {
 		return err
 	}
+	if version != 0 && media.Version != version {
+		return ErrVersionConflict
+	}
 
 	media.Visibility = visibility
 
-	err = s.MediaRepo.UpdateMedia(mediaID, media)
+	err = s.MediaRepo.UpdateMedia(mediaID, media, version)
 	if err != nil {
 		return fmt.Errorf("failed to update media: %w", err)
 	}
@@ -106,11 +134,16 @@ func (s *MediaService) GetMediaVisibility(mediaID int64, userID uint) (string, e
 	return string(media.Visibility), nil
 }
 
-func (s *MediaService) UpdateMediaMetadata(mediaID int64, i int64, latitude float64, longitude float64, altitude float64) error {
+// UpdateMediaMetadata fails with ErrVersionConflict when version is set and
+// the media has changed since.
+func (s *MediaService) UpdateMediaMetadata(mediaID int64, i int64, latitude float64, longitude float64, altitude float64, version int) error {
 	media, err := s.MediaRepo.GetMediaByID(mediaID)
 	if media == nil || err != nil {
 		return err
 	}
+	if version != 0 && media.Version != version {
+		return ErrVersionConflict
+	}
 
 	media.GpsLatitude = latitude
 	media.GpsLongitude = longitude
@@ -137,7 +170,7 @@ func (s *MediaService) UpdateMediaMetadata(mediaID int64, i int64, latitude floa
 		locationInfo.Country = locationCreated.Country
 	}
 
-	err = s.MediaRepo.UpdateMedia(media.MediaID, media)
+	err = s.MediaRepo.UpdateMedia(media.MediaID, media, version)
 	if err != nil {
 		return fmt.Errorf("failed to update media: %w", err)
 	}
@@ -163,15 +196,8 @@ func (s *MediaService) GetMediaURL(mediaID int64, userID int64) (string, error)
 	}
 
 	// Permissions check
-	switch media.Visibility {
-	case "PRIVATE":
-		if media.UserID != userID {
-			return "", fmt.Errorf("not authorized")
-		}
-	case "FRIENDS":
-		if !s.MediaRepo.AreFriends(userID, media.UserID) {
-			return "", fmt.Errorf("not authorized")
-		}
+	if !s.CanViewMedia(media, userID) {
+		return "", fmt.Errorf("not authorized")
 	}
 
 	// Get presigned URL using MinioService
@@ -245,19 +271,7 @@ func (s *MediaService) ExtractMetadata(file multipart.File, header *multipart.Fi
 	}
 	file.Seek(0, 0) // Reset file pointer
 
-	mimeType := http.DetectContentType(buffer)
-
-	// Check both extension and MIME type
-	switch {
-	case fileExt == ".jpg" || fileExt == ".jpeg" || fileExt == ".png" || fileExt == ".gif" || fileExt == ".webp" || fileExt == ".tiff" ||
-		strings.HasPrefix(mimeType, "image/"):
-		metadata.Type = "photo"
-	case fileExt == ".mp4" || fileExt == ".mov" || fileExt == ".avi" || fileExt == ".mkv" || fileExt == ".webm" || fileExt == ".flv" ||
-		strings.HasPrefix(mimeType, "video/"):
-		metadata.Type = "video"
-	default:
-		metadata.Type = "unknown"
-	}
+	metadata.Type = MediaTypeOf(fileExt, buffer)
 
 	if metadata.Type == "photo" {
 		exifData, err := exif.Decode(file)
@@ -278,32 +292,16 @@ func (s *MediaService) ExtractMetadata(file multipart.File, header *multipart.Fi
 				}
 
 				// Get location info if coordinates are available
-				if locationInfo, err := s.GetLocationInfo(lat, long); err == nil {
-					locationFound, err := s.GetLocationByCountryAndCity(locationInfo)
-
-					if err == nil {
-						metadata.LocationID = locationFound.LocationID
-						metadata.City = locationFound.City
-						metadata.Country = locationFound.Country
-					} else {
-						locationCreated, err := s.setLocationInfo(locationInfo)
-						if err == nil {
-							metadata.LocationID = locationCreated.LocationID
-							metadata.City = locationCreated.City
-							metadata.Country = locationCreated.Country
-						}
-					}
-
-				} else {
-					fmt.Printf("Failed to get location info: %v\n", err)
-				}
+				s.ResolveLocation(metadata)
 			} else {
 				fmt.Printf("No GPS coordinates found: %v\n", err)
 			}
 
 			// Get capture date
-			if dateTag, err := exifData.DateTime(); err == nil {
-				metadata.CaptureDate = dateTag
+			if captured, offsetKnown, err := exifCaptureTime(exifData); err == nil {
+				metadata.CaptureDate = captured
+				metadata.CaptureDateKnown = true
+				metadata.CaptureOffsetKnown = offsetKnown
 			} else {
 				fmt.Printf("No capture date found: %v\n", err)
 			}
@@ -325,6 +323,47 @@ func (s *MediaService) ExtractMetadata(file multipart.File, header *multipart.Fi
 	return metadata, nil
 }
 
+// MediaTypeOf classifies a file as "photo", "video" or "unknown" from its
+// extension and, when given, the first bytes of its content.
+func MediaTypeOf(fileExt string, head []byte) string {
+	mimeType := ""
+	if len(head) > 0 {
+		mimeType = http.DetectContentType(head)
+	}
+
+	// Check both extension and MIME type
+	switch {
+	case fileExt == ".jpg" || fileExt == ".jpeg" || fileExt == ".png" || fileExt == ".gif" || fileExt == ".webp" || fileExt == ".tiff" ||
+		strings.HasPrefix(mimeType, "image/"):
+		return "photo"
+	case fileExt == ".mp4" || fileExt == ".mov" || fileExt == ".avi" || fileExt == ".mkv" || fileExt == ".webm" || fileExt == ".flv" ||
+		strings.HasPrefix(mimeType, "video/"):
+		return "video"
+	}
+	return "unknown"
+}
+
+// ResolveLocation fills in the location of the metadata coordinates, reusing
+// the stored location of the same city when there is one.
+func (s *MediaService) ResolveLocation(metadata *models.MediaMetadata) {
+	locationInfo, err := s.GetLocationInfo(metadata.Latitude, metadata.Longitude)
+	if err != nil {
+		fmt.Printf("Failed to get location info: %v\n", err)
+		return
+	}
+
+	location, err := s.GetLocationByCountryAndCity(locationInfo)
+	if err != nil {
+		location, err = s.setLocationInfo(locationInfo)
+		if err != nil {
+			return
+		}
+	}
+	metadata.LocationID = location.LocationID
+	metadata.City = location.City
+	metadata.Country = location.Country
+}
+
 // Change from getLocationInfo to GetLocationInfo
 func (s *MediaService) GetLocationInfo(lat, long float64) (*models.Location, error) {
 	// Using OpenStreetMap Nominatim API (free, no API key required)
@@ -378,6 +417,28 @@ func (s *MediaService) setLocationInfo(location *models.Location) (*models.Locat
 	return location, nil
 }
 
+// LocationsOf loads the locations of the given media, keyed by location ID.
+func (s *MediaService) LocationsOf(media []models.Media) (map[int64]models.Location, error) {
+	seen := make(map[int64]bool)
+	var locationIDs []int64
+	for _, m := range media {
+		if m.LocationID != 0 && !seen[m.LocationID] {
+			seen[m.LocationID] = true
+			locationIDs = append(locationIDs, m.LocationID)
+		}
+	}
+
+	locations, err := s.MediaRepo.GetLocationsByIDs(locationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media locations: %w", err)
+	}
+	byID := make(map[int64]models.Location)
+	for _, location := range locations {
+		byID[location.LocationID] = location
+	}
+	return byID, nil
+}
+
 func (s *MediaService) GetLocationByMediaID(mediaID int64) (*models.Location, error) {
 	media, err := s.MediaRepo.GetMediaByID(mediaID)
 	if err != nil {
@@ -396,8 +457,10 @@ func (s *MediaService) GetMediaByID(mediaID int64) (*models.Media, error) {
 	return s.MediaRepo.GetMediaByID(mediaID)
 }
 
-func (s *MediaService) DeleteMediaCompletely(mediaID int64, userID int64) error {
-	// First get the media to check permissions and get the file path
+// TrashMedia moves the media to the trash. The stored file is only removed
+// when the trash is purged.
+func (s *MediaService) TrashMedia(mediaID int64, userID int64) error {
+	// First get the media to check permissions
 	media, err := s.MediaRepo.GetMediaByID(mediaID)
 	if err != nil {
 		return fmt.Errorf("failed to find media: %w", err)
@@ -408,13 +471,6 @@ func (s *MediaService) DeleteMediaCompletely(mediaID int64, userID int64) error
 		return fmt.Errorf("not authorized to delete this media")
 	}
 
-	// Delete from MinIO using MinioService
-	err = s.MinioService.DeleteObject(media.FilePath)
-	if err != nil {
-		return fmt.Errorf("failed to delete from storage: %w", err)
-	}
-
-	// Delete from database
 	err = s.MediaRepo.DeleteMedia(mediaID)
 	if err != nil {
 		return fmt.Errorf("failed to delete from database: %w", err)
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
 	"main/pkg/config"
 	"mime/multipart"
 	"time"
@@ -21,22 +22,28 @@ func NewMinioService() *MinioService {
 }
 
 func (s *MinioService) UploadFile(file multipart.File, header *multipart.FileHeader) (string, error) {
-	objectName := fmt.Sprintf("%d_%s", time.Now().UnixNano(), header.Filename)
+	return s.UploadObject(header.Filename, file, header.Size, header.Header.Get("Content-Type"))
+}
+
+// UploadObject stores size bytes read from reader under a new object name
+// derived from filename.
+func (s *MinioService) UploadObject(filename string, reader io.Reader, size int64, contentType string) (string, error) {
+	objectName := fmt.Sprintf("%d_%s", time.Now().UnixNano(), filename)
 	fmt.Printf("Generated object name: %s\n", objectName)
 
 	fmt.Printf("Uploading file to MinIO bucket '%s'. Size: %d, Content-Type: %s\n",
 		s.BucketName,
-		header.Size,
-		header.Header.Get("Content-Type"))
+		size,
+		contentType)
 
 	_, err := config.MinioClient.PutObject(
 		context.Background(),
 		s.BucketName,
 		objectName,
-		file,
-		header.Size,
+		reader,
+		size,
 		minio.PutObjectOptions{
-			ContentType: header.Header.Get("Content-Type"),
+			ContentType: contentType,
 		},
 	)
 
@@ -75,4 +82,24 @@ func (s *MinioService) DeleteObject(objectName string) error {
 		return fmt.Errorf("failed to delete object from MinIO: %w", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// GetObject opens the object for reading. The object is streamed as it is
+// read, nothing is buffered beyond the client's own buffers.
+func (s *MinioService) GetObject(objectName string) (io.ReadCloser, error) {
+	object, err := config.MinioClient.GetObject(
+		context.Background(),
+		s.BucketName,
+		objectName,
+		minio.GetObjectOptions{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	// GetObject is lazy, Stat surfaces a missing object before any byte is read
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, err
+	}
+	return object, nil
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"main/internal/models"
+	"mime/multipart"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// takeoutAlbum is a folder of a Google Photos export, imported as one trip.
+type takeoutAlbum struct {
+	folder      string
+	title       string
+	description string
+	items       []takeoutItem
+}
+
+type takeoutItem struct {
+	file      *zip.File
+	mediaType string
+	sidecar   *models.TakeoutSidecar
+}
+
+// takeoutDuplicate is the "(1)" Google Photos adds to duplicate file names.
+var takeoutDuplicate = regexp.MustCompile(`\(\d+\)$`)
+
+// planTakeout groups the photos and videos of a Google Takeout export by
+// folder and matches them with their JSON sidecars. It returns no albums when
+// the archive has no sidecars, as it is then not a Takeout export.
+func planTakeout(files []*zip.File) ([]takeoutAlbum, error) {
+	sidecars := make(map[string]*models.TakeoutSidecar)
+	// Sidecars by folder and the original file name they describe
+	sidecarsByTitle := make(map[string]*models.TakeoutSidecar)
+	albumInfo := make(map[string]models.TakeoutAlbum)
+	var mediaFiles []*zip.File
+
+	for _, file := range files {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if !strings.EqualFold(path.Ext(file.Name), ".json") {
+			mediaFiles = append(mediaFiles, file)
+			continue
+		}
+
+		if path.Base(file.Name) == "metadata.json" {
+			var album models.TakeoutAlbum
+			if err := readJSON(file, &album); err == nil {
+				albumInfo[path.Dir(file.Name)] = album
+			}
+			continue
+		}
+		// Other JSON files of the export are not sidecars, skip them
+		var sidecar models.TakeoutSidecar
+		if err := readJSON(file, &sidecar); err != nil || sidecar.Title == "" {
+			continue
+		}
+		sidecars[file.Name] = &sidecar
+		sidecarsByTitle[path.Dir(file.Name)+"/"+sidecar.Title] = &sidecar
+	}
+	if len(sidecars) == 0 {
+		return nil, nil
+	}
+
+	albums := make(map[string]*takeoutAlbum)
+	for _, file := range mediaFiles {
+		mediaType := MediaTypeOf(strings.ToLower(path.Ext(file.Name)), nil)
+		if mediaType == "unknown" {
+			continue
+		}
+
+		folder := path.Dir(file.Name)
+		album, ok := albums[folder]
+		if !ok {
+			info := albumInfo[folder]
+			album = &takeoutAlbum{folder: folder, title: strings.TrimSpace(info.Title), description: info.Description}
+			if album.title == "" {
+				album.title = path.Base(folder)
+			}
+			if album.title == "." {
+				album.title = "Google Photos"
+			}
+			albums[folder] = album
+		}
+		album.items = append(album.items, takeoutItem{
+			file:      file,
+			mediaType: mediaType,
+			sidecar:   findSidecar(file.Name, sidecars, sidecarsByTitle),
+		})
+	}
+
+	planned := []takeoutAlbum{}
+	for _, album := range albums {
+		sort.Slice(album.items, func(i, j int) bool {
+			return album.items[i].file.Name < album.items[j].file.Name
+		})
+		planned = append(planned, *album)
+	}
+	sort.Slice(planned, func(i, j int) bool {
+		return planned[i].folder < planned[j].folder
+	})
+	return planned, nil
+}
+
+// findSidecar follows the naming of Takeout: "IMG.jpg.json", the newer
+// "IMG.jpg.supplemental-metadata.json", names cut to 51 characters, and
+// "IMG.jpg(1).json" for "IMG(1).jpg". Edited copies ("IMG-edited.jpg") share
+// the sidecar of the original, found by its title.
+func findSidecar(name string, sidecars, sidecarsByTitle map[string]*models.TakeoutSidecar) *models.TakeoutSidecar {
+	folder, base := path.Dir(name), path.Base(name)
+	ext := path.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	duplicate := takeoutDuplicate.FindString(stem)
+	original := strings.TrimSuffix(stem, duplicate) + ext
+
+	candidates := []string{name + ".json", name + ".supplemental-metadata.json"}
+	if len(base)+len(".json") > 51 {
+		candidates = append(candidates, folder+"/"+base[:51-len(".json")]+".json")
+	}
+	if duplicate != "" {
+		candidates = append(candidates, folder+"/"+original+duplicate+".json")
+	}
+	for _, candidate := range candidates {
+		if sidecar, ok := sidecars[candidate]; ok {
+			return sidecar
+		}
+	}
+
+	for _, title := range []string{base, original, strings.TrimSuffix(stem, "-edited") + ext} {
+		if sidecar, ok := sidecarsByTitle[folder+"/"+title]; ok {
+			return sidecar
+		}
+	}
+	return nil
+}
+
+// importTakeoutAlbum creates the album's trip, saves each file as soon as it
+// is uploaded, then dates the trip by their capture dates.
+func (s *ImportService) importTakeoutAlbum(job *models.ImportJob, album takeoutAlbum, visibility models.VisibilityEnum) {
+	created, err := s.TripService.CreateTrip(models.Trip{
+		UserID:      job.UserID,
+		Name:        album.title,
+		Description: album.description,
+		Visibility:  string(visibility),
+	})
+	if err != nil {
+		fmt.Printf("Error: Import job %d failed to create the trip of %s - %v\n", job.JobID, album.folder, err)
+		job.ProcessedItems += len(album.items)
+		job.FailedItems += len(album.items)
+		s.saveProgress(job)
+		return
+	}
+	trip := created.(models.Trip)
+	job.TripIDs = append(job.TripIDs, trip.TripID)
+	s.saveProgress(job)
+
+	var startDate, endDate *models.Date
+	for _, item := range album.items {
+		media, dated, err := s.uploadTakeoutItem(job.UserID, item, visibility)
+		if err == nil {
+			media.TripID = int64(trip.TripID)
+			err = s.saveImportedMedia(media)
+		}
+		if err == nil && dated {
+			date := models.NewDate(media.CaptureDate)
+			if startDate == nil || date.Before(startDate.Time) {
+				startDate = &date
+			}
+			if endDate == nil || date.After(endDate.Time) {
+				endDate = &date
+			}
+		}
+		s.itemDone(job, item.file.Name, err)
+	}
+	if startDate == nil {
+		return
+	}
+
+	fields := map[string]any{"start_date": startDate, "end_date": endDate}
+	if err := s.TripService.TripRepo.UpdateTripFields(trip.TripID, fields, nil, 0, nil); err != nil {
+		fmt.Printf("Error: Import job %d failed to date the trip of %s - %v\n", job.JobID, album.folder, err)
+	}
+}
+
+// uploadTakeoutItem uploads a photo or video and returns its media, not saved
+// yet, and whether its capture date is known. EXIF data comes first, the
+// sidecar fills in the capture date and position when it lacks them.
+func (s *ImportService) uploadTakeoutItem(userID uint, item takeoutItem, visibility models.VisibilityEnum) (*models.Media, bool, error) {
+	reader, err := item.file.Open()
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.Close()
+
+	name := path.Base(item.file.Name)
+	size := int64(item.file.UncompressedSize64)
+	metadata := &models.MediaMetadata{Type: item.mediaType, CaptureDate: time.Now()}
+
+	var objectName string
+	if item.mediaType == "photo" {
+		if size > maxImportPhotoSize {
+			return nil, false, fmt.Errorf("photos are limited to %d MB", maxImportPhotoSize>>20)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, false, err
+		}
+		if extracted, _ := s.MediaService.ExtractMetadata(memoryFile{bytes.NewReader(data)}, &multipart.FileHeader{Filename: name}); extracted != nil {
+			metadata = extracted
+		}
+		s.applySidecar(metadata, item.sidecar)
+		objectName, err = s.MediaService.MinioService.UploadObject(name, bytes.NewReader(data), int64(len(data)), contentTypeOf(name, data))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to upload: %w", err)
+		}
+	} else {
+		s.applySidecar(metadata, item.sidecar)
+		objectName, err = s.MediaService.MinioService.UploadObject(name, reader, size, contentTypeOf(name, nil))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to upload: %w", err)
+		}
+	}
+
+	return &models.Media{
+		UserID:       int64(userID),
+		LocationID:   metadata.LocationID,
+		Type:         metadata.Type,
+		FilePath:     objectName,
+		Visibility:   visibility,
+		UploadDate:   time.Now(),
+		CaptureDate:  metadata.CaptureDate,
+		GpsLatitude:  metadata.Latitude,
+		GpsLongitude: metadata.Longitude,
+		GpsAltitude:  metadata.Altitude,
+	}, metadata.CaptureDateKnown, nil
+}
+
+func (s *ImportService) applySidecar(metadata *models.MediaMetadata, sidecar *models.TakeoutSidecar) {
+	if sidecar == nil {
+		return
+	}
+	// The sidecar's timestamp is in UTC, unlike an EXIF date without offset
+	if !metadata.CaptureDateKnown || !metadata.CaptureOffsetKnown {
+		if takenAt, ok := sidecar.TakenAt(); ok {
+			metadata.CaptureDate = takenAt
+			metadata.CaptureDateKnown = true
+			metadata.CaptureOffsetKnown = true
+		}
+	}
+	if metadata.Latitude == 0 && metadata.Longitude == 0 {
+		if position, ok := sidecar.Position(); ok {
+			metadata.Latitude = position.Latitude
+			metadata.Longitude = position.Longitude
+			metadata.Altitude = position.Altitude
+			s.MediaService.ResolveLocation(metadata)
+		}
+	}
+}
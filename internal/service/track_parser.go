@@ -0,0 +1,396 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"main/internal/models"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ParseTrack reads a GPX, TCX or FIT file into its name and points. The
+// format is taken from the content, falling back to the file extension.
+func ParseTrack(filename string, data []byte) (*models.Track, error) {
+	format := detectTrackFormat(filename, data)
+
+	var track *models.Track
+	var err error
+	switch format {
+	case models.TrackGPX:
+		track, err = parseGPX(data)
+	case models.TrackTCX:
+		track, err = parseTCX(data)
+	case models.TrackFIT:
+		track, err = parseFIT(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported track format, expected GPX, TCX or FIT", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s file: %v", ErrInvalidInput, strings.ToUpper(string(format)), err)
+	}
+	if len(track.Points) == 0 {
+		return nil, fmt.Errorf("%w: the track has no points", ErrInvalidInput)
+	}
+
+	track.Format = format
+	if track.Name == "" {
+		track.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	for i := range track.Points {
+		track.Points[i].Seq = i + 1
+	}
+	return track, nil
+}
+
+func detectTrackFormat(filename string, data []byte) models.TrackFormat {
+	if len(data) >= 12 && string(data[8:12]) == ".FIT" {
+		return models.TrackFIT
+	}
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	switch {
+	case bytes.Contains(head, []byte("<gpx")):
+		return models.TrackGPX
+	case bytes.Contains(head, []byte("<TrainingCenterDatabase")):
+		return models.TrackTCX
+	}
+	return models.TrackFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), "."))
+}
+
+// parseGPX collects the track points (trkpt) of every track, or the route
+// points (rtept) when there are none.
+func parseGPX(data []byte) (*models.Track, error) {
+	type gpxPoint struct {
+		Lat  float64  `xml:"lat,attr"`
+		Lon  float64  `xml:"lon,attr"`
+		Ele  *float64 `xml:"ele"`
+		Time string   `xml:"time"`
+	}
+
+	track := &models.Track{}
+	var routePoints []models.TrackPoint
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var parents []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "trkpt", "rtept":
+				var p gpxPoint
+				if err := decoder.DecodeElement(&p, &element); err != nil {
+					return nil, err
+				}
+				point, err := newTrackPoint(p.Lat, p.Lon, p.Ele, p.Time)
+				if err != nil {
+					return nil, err
+				}
+				if element.Name.Local == "trkpt" {
+					track.Points = append(track.Points, point)
+				} else {
+					routePoints = append(routePoints, point)
+				}
+				continue
+			case "name":
+				// The first trip or track name wins
+				parent := ""
+				if len(parents) > 0 {
+					parent = parents[len(parents)-1]
+				}
+				if track.Name == "" && (parent == "metadata" || parent == "trk") {
+					var name string
+					if err := decoder.DecodeElement(&name, &element); err != nil {
+						return nil, err
+					}
+					track.Name = strings.TrimSpace(name)
+					continue
+				}
+			}
+			parents = append(parents, element.Name.Local)
+		case xml.EndElement:
+			if len(parents) > 0 {
+				parents = parents[:len(parents)-1]
+			}
+		}
+	}
+
+	if len(track.Points) == 0 {
+		track.Points = routePoints
+	}
+	return track, nil
+}
+
+// parseTCX collects the trackpoints of activities and courses.
+func parseTCX(data []byte) (*models.Track, error) {
+	type tcxPoint struct {
+		Time     string `xml:"Time"`
+		Position *struct {
+			Lat float64 `xml:"LatitudeDegrees"`
+			Lon float64 `xml:"LongitudeDegrees"`
+		} `xml:"Position"`
+		Altitude *float64 `xml:"AltitudeMeters"`
+	}
+
+	track := &models.Track{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch element.Name.Local {
+		case "Trackpoint":
+			var p tcxPoint
+			if err := decoder.DecodeElement(&p, &element); err != nil {
+				return nil, err
+			}
+			// Indoor trackpoints carry no position
+			if p.Position == nil {
+				continue
+			}
+			point, err := newTrackPoint(p.Position.Lat, p.Position.Lon, p.Altitude, p.Time)
+			if err != nil {
+				return nil, err
+			}
+			track.Points = append(track.Points, point)
+		case "Name":
+			if track.Name == "" {
+				var name string
+				if err := decoder.DecodeElement(&name, &element); err != nil {
+					return nil, err
+				}
+				track.Name = strings.TrimSpace(name)
+			}
+		}
+	}
+	return track, nil
+}
+
+func newTrackPoint(lat, lon float64, elevation *float64, timestamp string) (models.TrackPoint, error) {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return models.TrackPoint{}, fmt.Errorf("coordinates %f,%f out of range", lat, lon)
+	}
+	point := models.TrackPoint{Latitude: lat, Longitude: lon, Elevation: elevation}
+	if timestamp = strings.TrimSpace(timestamp); timestamp != "" {
+		t, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			return models.TrackPoint{}, fmt.Errorf("invalid time %q", timestamp)
+		}
+		t = t.UTC()
+		point.Time = &t
+	}
+	return point, nil
+}
+
+// FIT constants, see the FIT protocol in the Garmin FIT SDK.
+const (
+	fitRecordMessage     = 20
+	fitTimestampField    = 253
+	fitLatitudeField     = 0
+	fitLongitudeField    = 1
+	fitAltitudeField     = 2
+	fitEnhancedAltitude  = 78
+	fitInvalidSint32     = 0x7FFFFFFF
+	fitInvalidUint32     = 0xFFFFFFFF
+	fitInvalidUint16     = 0xFFFF
+	fitSemicircleDegrees = 180.0 / (1 << 31)
+)
+
+// fitEpoch is the origin of FIT timestamps, 1989-12-31T00:00:00Z.
+var fitEpoch = time.Date(1989, 12, 31, 0, 0, 0, 0, time.UTC)
+
+type fitField struct {
+	number uint8
+	size   int
+}
+
+type fitDefinition struct {
+	globalMessage uint16
+	bigEndian     bool
+	fields        []fitField
+	// developerSize is the total size of the developer fields, skipped
+	developerSize int
+}
+
+// parseFIT decodes the record messages of a FIT activity file. Everything
+// else is skipped.
+func parseFIT(data []byte) (*models.Track, error) {
+	if len(data) < 12 {
+		return nil, errors.New("file too short")
+	}
+	headerSize := int(data[0])
+	if headerSize < 12 || len(data) < headerSize {
+		return nil, errors.New("invalid header")
+	}
+	end := headerSize + int(binary.LittleEndian.Uint32(data[4:8]))
+	if end > len(data) {
+		return nil, errors.New("truncated file")
+	}
+
+	track := &models.Track{}
+	definitions := make(map[uint8]*fitDefinition)
+	var lastTimestamp uint32
+	for pos := headerSize; pos < end; {
+		header := data[pos]
+		pos++
+
+		if header&0x80 != 0 {
+			// Compressed timestamp header: a data message with a 5-bit time offset
+			localType := (header >> 5) & 0x03
+			offset := uint32(header & 0x1F)
+			lastTimestamp += (offset - lastTimestamp&0x1F) & 0x1F
+			definition, ok := definitions[localType]
+			if !ok {
+				return nil, fmt.Errorf("undefined local message %d", localType)
+			}
+			next, err := readFITMessage(data, pos, end, definition, &lastTimestamp, track)
+			if err != nil {
+				return nil, err
+			}
+			pos = next
+			continue
+		}
+
+		localType := header & 0x0F
+		if header&0x40 != 0 {
+			definition, next, err := readFITDefinition(data, pos, end, header&0x20 != 0)
+			if err != nil {
+				return nil, err
+			}
+			definitions[localType] = definition
+			pos = next
+			continue
+		}
+
+		definition, ok := definitions[localType]
+		if !ok {
+			return nil, fmt.Errorf("undefined local message %d", localType)
+		}
+		next, err := readFITMessage(data, pos, end, definition, &lastTimestamp, track)
+		if err != nil {
+			return nil, err
+		}
+		pos = next
+	}
+	return track, nil
+}
+
+func readFITDefinition(data []byte, pos, end int, developer bool) (*fitDefinition, int, error) {
+	if pos+5 > end {
+		return nil, 0, errors.New("truncated definition")
+	}
+	definition := &fitDefinition{bigEndian: data[pos+1] == 1}
+	if definition.bigEndian {
+		definition.globalMessage = binary.BigEndian.Uint16(data[pos+2 : pos+4])
+	} else {
+		definition.globalMessage = binary.LittleEndian.Uint16(data[pos+2 : pos+4])
+	}
+	fieldCount := int(data[pos+4])
+	pos += 5
+
+	if pos+3*fieldCount > end {
+		return nil, 0, errors.New("truncated definition")
+	}
+	for i := 0; i < fieldCount; i++ {
+		definition.fields = append(definition.fields, fitField{number: data[pos], size: int(data[pos+1])})
+		pos += 3
+	}
+
+	if developer {
+		if pos >= end {
+			return nil, 0, errors.New("truncated definition")
+		}
+		developerCount := int(data[pos])
+		pos++
+		if pos+3*developerCount > end {
+			return nil, 0, errors.New("truncated definition")
+		}
+		for i := 0; i < developerCount; i++ {
+			definition.developerSize += int(data[pos+1])
+			pos += 3
+		}
+	}
+	return definition, pos, nil
+}
+
+// readFITMessage reads one data message, adding a point to the track when it
+// is a record with a position.
+func readFITMessage(data []byte, pos, end int, definition *fitDefinition, lastTimestamp *uint32, track *models.Track) (int, error) {
+	var order binary.ByteOrder = binary.LittleEndian
+	if definition.bigEndian {
+		order = binary.BigEndian
+	}
+
+	lat, lon := int32(fitInvalidSint32), int32(fitInvalidSint32)
+	var elevation *float64
+	for _, field := range definition.fields {
+		if pos+field.size > end {
+			return 0, errors.New("truncated message")
+		}
+		value := data[pos : pos+field.size]
+		pos += field.size
+
+		switch {
+		case field.number == fitTimestampField && field.size == 4:
+			if timestamp := order.Uint32(value); timestamp != fitInvalidUint32 {
+				*lastTimestamp = timestamp
+			}
+		case definition.globalMessage != fitRecordMessage:
+		case field.number == fitLatitudeField && field.size == 4:
+			lat = int32(order.Uint32(value))
+		case field.number == fitLongitudeField && field.size == 4:
+			lon = int32(order.Uint32(value))
+		case field.number == fitAltitudeField && field.size == 2 && elevation == nil:
+			if raw := order.Uint16(value); raw != fitInvalidUint16 {
+				altitude := float64(raw)/5 - 500
+				elevation = &altitude
+			}
+		case field.number == fitEnhancedAltitude && field.size == 4:
+			if raw := order.Uint32(value); raw != fitInvalidUint32 {
+				altitude := float64(raw)/5 - 500
+				elevation = &altitude
+			}
+		}
+	}
+	if pos+definition.developerSize > end {
+		return 0, errors.New("truncated message")
+	}
+	pos += definition.developerSize
+
+	if definition.globalMessage == fitRecordMessage && lat != fitInvalidSint32 && lon != fitInvalidSint32 {
+		point := models.TrackPoint{
+			Latitude:  math.Round(float64(lat)*fitSemicircleDegrees*1e7) / 1e7,
+			Longitude: math.Round(float64(lon)*fitSemicircleDegrees*1e7) / 1e7,
+			Elevation: elevation,
+		}
+		if *lastTimestamp != 0 {
+			t := fitEpoch.Add(time.Duration(*lastTimestamp) * time.Second)
+			point.Time = &t
+		}
+		track.Points = append(track.Points, point)
+	}
+	return pos, nil
+}
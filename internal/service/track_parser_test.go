@@ -0,0 +1,180 @@
+package service
+
+import (
+	"encoding/binary"
+	"errors"
+	"main/internal/models"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type wantPoint struct {
+	lat, lon  float64
+	elevation *float64
+	time      string
+}
+
+func elevation(meters float64) *float64 {
+	return &meters
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return data
+}
+
+func TestParseTrack(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		filename string
+		format   models.TrackFormat
+		track    string
+		points   []wantPoint
+	}{
+		{
+			name:     "GPX track points in UTC",
+			fixture:  "track.gpx",
+			filename: "ride.gpx",
+			format:   models.TrackGPX,
+			track:    "Morning ride",
+			points: []wantPoint{
+				{48.8566, 2.3522, elevation(35), "2024-06-01T08:00:00Z"},
+				{48.8570, 2.3530, elevation(36.5), "2024-06-01T08:00:30Z"},
+				{48.8580, 2.3540, nil, "2024-06-01T08:01:00Z"},
+			},
+		},
+		{
+			name:     "GPX route without times named after the file",
+			fixture:  "route.gpx",
+			filename: "alps.gpx",
+			format:   models.TrackGPX,
+			track:    "alps",
+			points: []wantPoint{
+				{45.0, 7.0, nil, ""},
+				{45.1, 7.1, nil, ""},
+			},
+		},
+		{
+			name:     "TCX skips trackpoints without position",
+			fixture:  "track.tcx",
+			filename: "run.tcx",
+			format:   models.TrackTCX,
+			track:    "run",
+			points: []wantPoint{
+				{51.5007, -0.1246, elevation(12), "2024-06-02T06:30:00Z"},
+				{51.5010, -0.1250, nil, "2024-06-02T06:30:10Z"},
+			},
+		},
+		{
+			name:     "FIT records with compressed timestamps",
+			fixture:  "track.fit",
+			filename: "activity.fit",
+			format:   models.TrackFIT,
+			track:    "activity",
+			points: []wantPoint{
+				{40.7128, -74.0060, elevation(10), "2024-06-03T09:00:00Z"},
+				{40.7130, -74.0050, nil, "2024-06-03T09:00:10Z"},
+				{40.7135, -74.0040, nil, "2024-06-03T09:00:15Z"},
+			},
+		},
+		{
+			name:     "format detected from content, not extension",
+			fixture:  "track.fit",
+			filename: "activity.gpx",
+			format:   models.TrackFIT,
+			track:    "activity",
+			points: []wantPoint{
+				{40.7128, -74.0060, elevation(10), "2024-06-03T09:00:00Z"},
+				{40.7130, -74.0050, nil, "2024-06-03T09:00:10Z"},
+				{40.7135, -74.0040, nil, "2024-06-03T09:00:15Z"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			track, err := ParseTrack(tt.filename, readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("ParseTrack() error = %v", err)
+			}
+			if track.Format != tt.format {
+				t.Errorf("Format = %q, want %q", track.Format, tt.format)
+			}
+			if track.Name != tt.track {
+				t.Errorf("Name = %q, want %q", track.Name, tt.track)
+			}
+			if len(track.Points) != len(tt.points) {
+				t.Fatalf("got %d points, want %d", len(track.Points), len(tt.points))
+			}
+			for i, want := range tt.points {
+				got := track.Points[i]
+				if got.Seq != i+1 {
+					t.Errorf("point %d: Seq = %d, want %d", i, got.Seq, i+1)
+				}
+				if got.Latitude != want.lat || got.Longitude != want.lon {
+					t.Errorf("point %d: position = %f,%f, want %f,%f", i, got.Latitude, got.Longitude, want.lat, want.lon)
+				}
+				switch {
+				case want.elevation == nil && got.Elevation != nil:
+					t.Errorf("point %d: Elevation = %f, want none", i, *got.Elevation)
+				case want.elevation != nil && (got.Elevation == nil || *got.Elevation != *want.elevation):
+					t.Errorf("point %d: Elevation = %v, want %f", i, got.Elevation, *want.elevation)
+				}
+				if want.time == "" {
+					if got.Time != nil {
+						t.Errorf("point %d: Time = %v, want none", i, got.Time)
+					}
+					continue
+				}
+				wantTime, _ := time.Parse(time.RFC3339, want.time)
+				if got.Time == nil || !got.Time.Equal(wantTime) || got.Time.Location() != time.UTC {
+					t.Errorf("point %d: Time = %v, want %v in UTC", i, got.Time, wantTime)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTrackRejectsInvalidFiles(t *testing.T) {
+	fit := readFixture(t, "track.fit")
+	// withDataSize copies the header of the fixture, declaring size bytes of
+	// records, followed by body
+	withDataSize := func(size int, body []byte) []byte {
+		data := append([]byte{}, fit[:12]...)
+		binary.LittleEndian.PutUint32(data[4:8], uint32(size))
+		return append(data, body...)
+	}
+	// The first definition message takes 18 bytes after the header
+	records := fit[30 : len(fit)-2]
+
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+	}{
+		{"truncated FIT", "activity.fit", fit[:len(fit)-12]},
+		{"FIT cut in the middle of a record", "activity.fit", withDataSize(23, fit[12:35])},
+		{"FIT cut in the middle of a definition", "activity.fit", withDataSize(10, fit[12:22])},
+		{"FIT shorter than its header", "activity.fit", fit[:10]},
+		{"FIT record before its definition", "activity.fit", withDataSize(len(records), records)},
+		{"GPX coordinates out of range", "invalid.gpx", readFixture(t, "invalid.gpx")},
+		{"malformed XML", "broken.gpx", []byte("<gpx><trk><trkpt lat=\"1\" lon=\"2\">")},
+		{"GPX without points", "empty.gpx", []byte(`<gpx version="1.1"></gpx>`)},
+		{"unknown format", "notes.txt", []byte("hello")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTrack(tt.filename, tt.data); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("ParseTrack() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"main/internal/db"
+	"main/internal/events"
+	"main/internal/models"
+	"math"
+	"time"
+)
+
+// trackMatchWindow is how far from a track point a capture time may be for
+// media to be placed on it when it cannot be interpolated.
+const trackMatchWindow = 5 * time.Minute
+
+type TrackService struct {
+	TrackRepo    *db.TracksRepository
+	TripService  *TripService
+	MediaService *MediaService
+}
+
+func (s *TrackService) GetTracks(tripID int, userID uint) ([]models.Track, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.TripService.CanViewTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+
+	tracks, err := s.TrackRepo.GetTracksByTripID(tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tracks: %w", err)
+	}
+	return tracks, nil
+}
+
+// ImportTrip creates a trip from a track file, named after the track unless
+// name is set and dated by its time span.
+func (s *TrackService) ImportTrip(userID uint, filename string, data []byte, name string, visibility models.VisibilityEnum) (models.Trip, *models.Track, error) {
+	switch visibility {
+	case "":
+		visibility = models.Private
+	case models.Public, models.Friends, models.Private:
+	default:
+		return models.Trip{}, nil, fmt.Errorf("%w: invalid visibility %q", ErrInvalidInput, visibility)
+	}
+
+	track, err := ParseTrack(filename, data)
+	if err != nil {
+		return models.Trip{}, nil, err
+	}
+	summarizeTrack(track)
+
+	trip := models.Trip{
+		UserID:     userID,
+		Name:       name,
+		Visibility: string(visibility),
+	}
+	if trip.Name == "" {
+		trip.Name = track.Name
+	}
+	if track.StartedAt != nil {
+		start, end := models.NewDate(*track.StartedAt), models.NewDate(*track.EndedAt)
+		trip.StartDate, trip.EndDate = &start, &end
+	}
+
+	track.UploadedBy = userID
+	if err := s.TrackRepo.CreateTripWithTrack(&trip, track); err != nil {
+		return models.Trip{}, nil, fmt.Errorf("failed to save track: %w", err)
+	}
+
+	if s.TripService.Events != nil {
+		evt := events.TripCreatedEvent{
+			TripID:    trip.TripID,
+			OwnerID:   userID,
+			CreatedAt: time.Now(),
+		}
+		_ = s.TripService.Events.Publish("trip.created", evt)
+	}
+
+	return trip, track, nil
+}
+
+// AddTrack stores a track file against an existing trip, widening the trip's
+// dates to cover it.
+func (s *TrackService) AddTrack(tripID int, userID uint, filename string, data []byte) (*models.Track, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canEdit, err := s.TripService.CanEditTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canEdit {
+		return nil, ErrNotAuthorized
+	}
+
+	track, err := ParseTrack(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	summarizeTrack(track)
+
+	track.TripID = tripID
+	track.UploadedBy = userID
+	if err := s.TrackRepo.CreateTrack(track); err != nil {
+		return nil, fmt.Errorf("failed to save track: %w", err)
+	}
+
+	if err := s.widenTripDates(trip, track, userID); err != nil {
+		fmt.Printf("Error: Failed to update the dates of trip %d from track %d - %v\n", tripID, track.TrackID, err)
+	}
+	return track, nil
+}
+
+// DeleteTrack is allowed to the uploader and the trip owner. The trip dates
+// are left as they are.
+func (s *TrackService) DeleteTrack(tripID int, trackID int64, userID uint) error {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return err
+	}
+	role, err := s.TripService.GetUserRole(trip, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return ErrNotAuthorized
+	}
+
+	track, err := s.TrackRepo.GetTrackByID(trackID)
+	if err != nil {
+		return err
+	}
+	if track.TripID != tripID {
+		return fmt.Errorf("%w: track does not belong to this trip", ErrInvalidInput)
+	}
+	if track.UploadedBy != userID && role != models.RoleOwner {
+		return ErrNotAuthorized
+	}
+
+	return s.TrackRepo.DeleteTrack(trackID)
+}
+
+// LocateByTrack places media without GPS on the trip's tracks by capture
+// time. The position is interpolated between the points around the capture
+// time when they belong to the same track, otherwise the nearest point is
+// used if it is close enough in time. Track points are in UTC, so media whose
+// UTC offset is unknown are not placed. It reports whether a position was
+// found.
+func (s *TrackService) LocateByTrack(tripID int, metadata *models.MediaMetadata) bool {
+	if !metadata.CaptureDateKnown || !metadata.CaptureOffsetKnown {
+		return false
+	}
+
+	before, after, err := s.TrackRepo.GetPointsAround(tripID, metadata.CaptureDate)
+	if err != nil {
+		fmt.Printf("Failed to get track points for trip %d: %v\n", tripID, err)
+		return false
+	}
+
+	lat, lon, elevation, ok := positionAt(metadata.CaptureDate, before, after)
+	if !ok {
+		return false
+	}
+
+	metadata.Latitude = lat
+	metadata.Longitude = lon
+	if elevation != nil {
+		metadata.Altitude = *elevation
+	}
+	s.MediaService.ResolveLocation(metadata)
+	metadata.RequiresManualLocation = metadata.City == "" && metadata.Country == ""
+	return true
+}
+
+func positionAt(t time.Time, before, after *models.TrackPoint) (float64, float64, *float64, bool) {
+	if before != nil && after != nil && before.TrackID == after.TrackID {
+		span := after.Time.Sub(*before.Time)
+		if span <= 0 {
+			return before.Latitude, before.Longitude, before.Elevation, true
+		}
+		ratio := float64(t.Sub(*before.Time)) / float64(span)
+		elevation := before.Elevation
+		if before.Elevation != nil && after.Elevation != nil {
+			interpolated := *before.Elevation + (*after.Elevation-*before.Elevation)*ratio
+			elevation = &interpolated
+		}
+		return before.Latitude + (after.Latitude-before.Latitude)*ratio,
+			before.Longitude + (after.Longitude-before.Longitude)*ratio,
+			elevation, true
+	}
+
+	var nearest *models.TrackPoint
+	gap := trackMatchWindow + 1
+	for _, point := range []*models.TrackPoint{before, after} {
+		if point == nil {
+			continue
+		}
+		if d := point.Time.Sub(t).Abs(); d < gap {
+			nearest, gap = point, d
+		}
+	}
+	if nearest == nil || gap > trackMatchWindow {
+		return 0, 0, nil, false
+	}
+	return nearest.Latitude, nearest.Longitude, nearest.Elevation, true
+}
+
+// summarizeTrack sets the time span, point count and distance of a parsed
+// track.
+func summarizeTrack(track *models.Track) {
+	track.PointCount = len(track.Points)
+	track.DistanceKm = 0
+	for i, point := range track.Points {
+		if i > 0 {
+			previous := track.Points[i-1]
+			track.DistanceKm += haversineKm(previous.Latitude, previous.Longitude, point.Latitude, point.Longitude)
+		}
+		if point.Time == nil {
+			continue
+		}
+		if track.StartedAt == nil || point.Time.Before(*track.StartedAt) {
+			track.StartedAt = point.Time
+		}
+		if track.EndedAt == nil || point.Time.After(*track.EndedAt) {
+			track.EndedAt = point.Time
+		}
+	}
+	track.DistanceKm = math.Round(track.DistanceKm*100) / 100
+}
+
+// widenTripDates extends the trip's start and end dates so that they cover the
+// track, recording the change like any other edit.
+func (s *TrackService) widenTripDates(trip models.Trip, track *models.Track, userID uint) error {
+	if track.StartedAt == nil {
+		return nil
+	}
+
+	patch := models.TripPatch{}
+	start, end := models.NewDate(*track.StartedAt), models.NewDate(*track.EndedAt)
+	if trip.StartDate == nil || start.Before(trip.StartDate.Time) {
+		value, _ := json.Marshal(start)
+		patch["start_date"] = value
+	}
+	if trip.EndDate == nil || end.After(trip.EndDate.Time) {
+		value, _ := json.Marshal(end)
+		patch["end_date"] = value
+	}
+	if len(patch) == 0 {
+		return nil
+	}
+
+	_, err := s.TripService.PatchTrip(trip.TripID, userID, patch, 0)
+	return err
+}
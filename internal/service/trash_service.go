@@ -0,0 +1,179 @@
+package service
+
+import (
+	"fmt"
+	"main/internal/db"
+	"main/internal/models"
+	"strconv"
+	"time"
+)
+
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// trashPurgeLockKey is the advisory lock taken while purging the trash.
+const trashPurgeLockKey int64 = 7_411_031
+
+type TrashService struct {
+	TripService  *TripService
+	MediaService *MediaService
+	Retention    time.Duration
+}
+
+func (s *TrashService) GetTrash(userID uint) (*models.Trash, error) {
+	trips, err := s.TripService.TripRepo.GetTrashedTrips(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed trips: %w", err)
+	}
+	media, err := s.MediaService.MediaRepo.GetTrashedMedia(int64(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed media: %w", err)
+	}
+
+	trash := &models.Trash{
+		Trips: []models.TrashedTrip{},
+		Media: []models.TrashedMedia{},
+	}
+	for _, trip := range trips {
+		trash.Trips = append(trash.Trips, models.TrashedTrip{
+			Trip:      trip,
+			DeletedAt: trip.DeletedAt.Time,
+			PurgeAt:   trip.DeletedAt.Time.Add(s.retention()),
+		})
+	}
+	for _, m := range media {
+		trash.Media = append(trash.Media, models.TrashedMedia{
+			MediaID:   m.MediaID,
+			TripID:    m.TripID,
+			Type:      m.Type,
+			DeletedAt: m.DeletedAt.Time,
+			PurgeAt:   m.DeletedAt.Time.Add(s.retention()),
+		})
+	}
+	return trash, nil
+}
+
+// RestoreTrip brings back a trashed trip with the album links and media that
+// were trashed along with it. Only owners can restore their trips.
+func (s *TrashService) RestoreTrip(tripID int, userID uint) (models.Trip, error) {
+	trip, err := s.TripService.TripRepo.GetTrashedTripByID(tripID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	if trip.UserID != userID {
+		return models.Trip{}, ErrNotAuthorized
+	}
+
+	if err := s.TripService.TripRepo.RestoreTrip(tripID, trip.DeletedAt.Time); err != nil {
+		return models.Trip{}, fmt.Errorf("failed to restore trip: %w", err)
+	}
+
+	return s.TripService.GetTripDetails(strconv.Itoa(tripID), userID)
+}
+
+func (s *TrashService) RestoreMedia(mediaID int64, userID uint) (*models.Media, error) {
+	media, err := s.MediaService.MediaRepo.GetTrashedMediaByID(mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.UserID != int64(userID) {
+		return nil, ErrNotAuthorized
+	}
+	if _, err := s.TripService.TripRepo.GetTripByID(int(media.TripID)); err != nil {
+		return nil, fmt.Errorf("%w: the media's trip is in the trash, restore the trip first", ErrInvalidInput)
+	}
+
+	if err := s.MediaService.MediaRepo.RestoreMedia(mediaID); err != nil {
+		return nil, fmt.Errorf("failed to restore media: %w", err)
+	}
+
+	return s.MediaService.MediaRepo.GetMediaByID(mediaID)
+}
+
+// Purge removes for good whatever has been in the trash longer than the
+// retention window, including the stored files no other media still uses.
+// A media item or trip that fails is logged and left for the next run.
+func (s *TrashService) Purge() error {
+	cutoff := time.Now().Add(-s.retention())
+
+	media, err := s.MediaService.MediaRepo.GetMediaTrashedBefore(cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to get trashed media: %w", err)
+	}
+	purgedMedia := 0
+	for _, m := range media {
+		if err := s.purgeMedia(m); err != nil {
+			fmt.Printf("Error: Failed to purge media %d - %v\n", m.MediaID, err)
+			continue
+		}
+		purgedMedia++
+	}
+
+	trips, err := s.TripService.TripRepo.GetTripsTrashedBefore(cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to get trashed trips: %w", err)
+	}
+	purgedTrips := 0
+	for _, trip := range trips {
+		if err := s.purgeTrip(trip.TripID); err != nil {
+			fmt.Printf("Error: Failed to purge trip %d - %v\n", trip.TripID, err)
+			continue
+		}
+		purgedTrips++
+	}
+
+	fmt.Printf("Purged %d media and %d trips from the trash\n", purgedMedia, purgedTrips)
+	return nil
+}
+
+func (s *TrashService) purgeMedia(m models.Media) error {
+	orphaned, err := s.MediaService.MediaRepo.PurgeMedia(m.MediaID)
+	if err != nil {
+		return err
+	}
+	s.deleteFiles(orphaned)
+	return nil
+}
+
+// purgeTrip removes the trip with all of its media, including the media that
+// was left in place when the trip was trashed.
+func (s *TrashService) purgeTrip(tripID int) error {
+	orphaned, err := s.TripService.TripRepo.PurgeTrip(tripID)
+	if err != nil {
+		return err
+	}
+	s.deleteFiles(orphaned)
+	return nil
+}
+
+// deleteFiles removes stored files once the rows pointing at them are gone. A
+// file that fails is only logged, since nothing references it any more.
+func (s *TrashService) deleteFiles(filePaths []string) {
+	for _, filePath := range filePaths {
+		if err := s.MediaService.MinioService.DeleteObject(filePath); err != nil {
+			fmt.Printf("Error: Failed to delete orphaned file %s - %v\n", filePath, err)
+		}
+	}
+}
+
+// RunPurge purges the trash every interval until the process exits. Every
+// replica runs it, so an advisory lock lets only one of them purge at a time.
+func (s *TrashService) RunPurge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ran, err := db.WithAdvisoryLock(s.TripService.TripRepo.DB, trashPurgeLockKey, s.Purge)
+		if err != nil {
+			fmt.Printf("Error: Failed to purge trash - %v\n", err)
+		} else if !ran {
+			fmt.Printf("Skipping trash purge, another instance is running it\n")
+		}
+		<-ticker.C
+	}
+}
+
+func (s *TrashService) retention() time.Duration {
+	if s.Retention <= 0 {
+		return DefaultTrashRetention
+	}
+	return s.Retention
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"main/internal/models"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// GPXLinkExpiry is how long the links in GPX files work, as they are
+	// usually loaded into a GPS app before the trip
+	GPXLinkExpiry = 24 * time.Hour
+	// MapLinkExpiry is how long the links in GeoJSON and KML files work
+	MapLinkExpiry = time.Hour
+)
+
+type TripExportService struct {
+	TripService  *TripService
+	MediaService *MediaService
+}
+
+// ExportGPX renders the trip's route as a GPX 1.1 document.
+func (s *TripExportService) ExportGPX(tripID int, userID uint) (*models.Trip, []byte, error) {
+	route, err := s.GetRoute(tripID, userID, GPXLinkExpiry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gpx, err := models.NewGPX(*route, time.Now()).Encode()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode GPX: %w", err)
+	}
+	return &route.Trip, gpx, nil
+}
+
+// ExportGeoJSON renders the trip's route and media as a GeoJSON feature
+// collection.
+func (s *TripExportService) ExportGeoJSON(tripID int, userID uint) (*models.Trip, []byte, error) {
+	route, err := s.GetRoute(tripID, userID, MapLinkExpiry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	geoJSON, err := json.MarshalIndent(models.NewGeoJSON(*route), "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode GeoJSON: %w", err)
+	}
+	return &route.Trip, geoJSON, nil
+}
+
+// ExportKML renders the trip's route and media as a KML 2.2 document.
+func (s *TripExportService) ExportKML(tripID int, userID uint) (*models.Trip, []byte, error) {
+	route, err := s.GetRoute(tripID, userID, MapLinkExpiry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kml, err := models.NewKML(*route).Encode()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode KML: %w", err)
+	}
+	return &route.Trip, kml, nil
+}
+
+// GetRoute returns the geotagged media of the trip the user can see, in
+// capture order, with links to the files valid for linkExpiry.
+func (s *TripExportService) GetRoute(tripID int, userID uint, linkExpiry time.Duration) (*models.TripRoute, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.TripService.CanViewTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+
+	media, err := s.MediaService.GetMediaDataByTripID(int64(tripID), int64(userID))
+	if err != nil {
+		return nil, err
+	}
+	var geotagged []models.Media
+	for _, m := range media {
+		if hasGPS(m) {
+			geotagged = append(geotagged, m)
+		}
+	}
+	sortByCaptureDate(geotagged)
+
+	locations, err := s.MediaService.LocationsOf(geotagged)
+	if err != nil {
+		return nil, err
+	}
+
+	route := &models.TripRoute{Trip: trip, Points: []models.RoutePoint{}}
+	counts := make(map[string]int)
+	for _, m := range geotagged {
+		location := locations[m.LocationID]
+		counts[m.Type]++
+		point := models.RoutePoint{
+			MediaID:   m.MediaID,
+			Type:      m.Type,
+			Latitude:  m.GpsLatitude,
+			Longitude: m.GpsLongitude,
+			Altitude:  m.GpsAltitude,
+			Time:      m.CaptureDate,
+			City:      location.City,
+			Country:   location.Country,
+			Name:      pointName(m.Type, counts[m.Type], location),
+		}
+		url, err := s.MediaService.MinioService.GetPresignedURL(m.FilePath, linkExpiry)
+		if err != nil {
+			fmt.Printf("Error: Failed to get URL of media %d - %v\n", m.MediaID, err)
+		} else {
+			point.URL = url
+		}
+		route.Points = append(route.Points, point)
+	}
+
+	return route, nil
+}
+
+// pointName numbers the media of each type and adds where they were taken
+// when known.
+func pointName(mediaType string, n int, location models.Location) string {
+	var name string
+	switch mediaType {
+	case "photo":
+		name = fmt.Sprintf("Photo %d", n)
+	case "video":
+		name = fmt.Sprintf("Video %d", n)
+	default:
+		name = fmt.Sprintf("Media %d", n)
+	}
+	var place []string
+	for _, part := range []string{location.City, location.Country} {
+		if part != "" {
+			place = append(place, part)
+		}
+	}
+	if len(place) > 0 {
+		name += " – " + strings.Join(place, ", ")
+	}
+	return name
+}
+
+// PrepareArchive lists what goes into the trip's archive: the media the user
+// can see, leaving out PRIVATE media unless includePrivate is set, which only
+// the owner may do.
+func (s *TripExportService) PrepareArchive(tripID int, userID uint, includePrivate bool) (*models.TripArchiveManifest, error) {
+	trip, err := s.TripService.GetTripByID(strconv.Itoa(tripID))
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.TripService.CanViewTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+	if includePrivate {
+		role, err := s.TripService.GetUserRole(trip, userID)
+		if err != nil {
+			return nil, err
+		}
+		if role != models.RoleOwner {
+			return nil, ErrNotAuthorized
+		}
+	}
+
+	// The only PRIVATE media the user can see are their own
+	visible, err := s.MediaService.GetMediaDataByTripID(int64(tripID), int64(userID))
+	if err != nil {
+		return nil, err
+	}
+	var media []models.Media
+	for _, m := range visible {
+		if m.Visibility == models.Private && !includePrivate {
+			continue
+		}
+		media = append(media, m)
+	}
+	sortByCaptureDate(media)
+
+	locations, err := s.MediaService.LocationsOf(media)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &models.TripArchiveManifest{
+		ExportedAt: time.Now().UTC(),
+		Trip:       trip,
+		Media:      []models.ArchivedMedia{},
+		Locations:  []models.Location{},
+	}
+	for _, m := range media {
+		manifest.Media = append(manifest.Media, models.ArchivedMedia{
+			Media: m,
+			File:  fmt.Sprintf("media/%d_%s", m.MediaID, path.Base(m.FilePath)),
+		})
+	}
+	for _, location := range locations {
+		manifest.Locations = append(manifest.Locations, location)
+	}
+	sort.Slice(manifest.Locations, func(i, j int) bool {
+		return manifest.Locations[i].LocationID < manifest.Locations[j].LocationID
+	})
+
+	return manifest, nil
+}
+
+// WriteArchive streams the originals listed in the manifest from storage into
+// a ZIP written to w, then the manifest itself. Originals that cannot be
+// opened are flagged as missing in the manifest; an error once a file is
+// being copied aborts the archive, as the response is already under way.
+func (s *TripExportService) WriteArchive(w io.Writer, manifest *models.TripArchiveManifest) error {
+	archive := zip.NewWriter(w)
+
+	for i := range manifest.Media {
+		m := &manifest.Media[i]
+		object, err := s.MediaService.MinioService.GetObject(m.FilePath)
+		if err != nil {
+			fmt.Printf("Error: Failed to open media %d for the archive - %v\n", m.MediaID, err)
+			m.Missing = true
+			continue
+		}
+
+		// Photos and videos are already compressed
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     m.File,
+			Method:   zip.Store,
+			Modified: m.CaptureDate,
+		})
+		if err == nil {
+			_, err = io.Copy(entry, object)
+		}
+		object.Close()
+		if err != nil {
+			return fmt.Errorf("failed to archive media %d: %w", m.MediaID, err)
+		}
+	}
+
+	entry, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     models.ArchiveManifestName,
+		Method:   zip.Deflate,
+		Modified: manifest.ExportedAt,
+	})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return archive.Close()
+}
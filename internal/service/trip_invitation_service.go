@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"main/internal/db"
+	"main/internal/events"
+	"main/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const invitationTTL = 14 * 24 * time.Hour
+
+type TripInvitationService struct {
+	InvitationRepo *db.TripInvitationsRepository
+	TripService    *TripService
+	Events         *events.Publisher
+}
+
+// Invite creates a pending invitation. Invitees join as viewers unless the
+// owner asks for another role.
+func (s *TripInvitationService) Invite(tripID int, inviterID uint, inviteeID uint, role models.TripRole) (*models.TripInvitation, error) {
+	if role == "" {
+		role = models.RoleViewer
+	}
+	if !role.IsValid() {
+		return nil, fmt.Errorf("%w: role must be one of owner, editor or viewer", ErrInvalidInput)
+	}
+
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	inviterRole, err := s.TripService.GetUserRole(trip, inviterID)
+	if err != nil {
+		return nil, err
+	}
+	if inviterRole != models.RoleOwner {
+		return nil, ErrNotAuthorized
+	}
+	inviteeRole, err := s.TripService.GetUserRole(trip, inviteeID)
+	if err != nil {
+		return nil, err
+	}
+	if inviteeRole != "" {
+		return nil, fmt.Errorf("%w: user is already a member of this trip", ErrInvalidInput)
+	}
+
+	_, err = s.InvitationRepo.GetPendingInvitation(tripID, inviteeID)
+	if err == nil {
+		return nil, fmt.Errorf("%w: user already has a pending invitation", ErrInvalidInput)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &models.TripInvitation{
+		TripID:    tripID,
+		InviterID: inviterID,
+		InviteeID: inviteeID,
+		Role:      role,
+		Status:    models.InvitationPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(invitationTTL),
+	}
+	if err := s.InvitationRepo.CreateInvitation(invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	if s.Events != nil {
+		evt := events.TripInvitationCreatedEvent{
+			InvitationID: invitation.InvitationID,
+			TripID:       tripID,
+			InviterID:    inviterID,
+			InviteeID:    inviteeID,
+			Role:         string(role),
+			ExpiresAt:    invitation.ExpiresAt,
+			CreatedAt:    now,
+		}
+		_ = s.Events.Publish("trip.invitation.created", evt)
+	}
+
+	return invitation, nil
+}
+
+func (s *TripInvitationService) GetMyPendingInvitations(userID uint) ([]models.TripInvitation, error) {
+	if err := s.InvitationRepo.ExpireInvitations(); err != nil {
+		fmt.Printf("Warning: Failed to expire invitations: %v\n", err)
+	}
+	return s.InvitationRepo.GetPendingInvitationsForUser(userID)
+}
+
+// Respond accepts or declines an invitation addressed to userID. Accepting
+// adds the invitee to the trip with the invited role; members keep the role
+// they already have.
+func (s *TripInvitationService) Respond(invitationID int64, userID uint, accept bool) (*models.TripInvitation, error) {
+	invitation, err := s.InvitationRepo.GetInvitationByID(invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.InviteeID != userID {
+		return nil, ErrNotAuthorized
+	}
+
+	now := time.Now()
+	if invitation.Status == models.InvitationPending && !invitation.ExpiresAt.After(now) {
+		invitation.Status = models.InvitationExpired
+		if err := s.InvitationRepo.ExpireInvitation(invitationID); err != nil {
+			return nil, err
+		}
+	}
+	if invitation.Status != models.InvitationPending {
+		return nil, fmt.Errorf("%w: invitation is %s", ErrInvalidInput, invitation.Status)
+	}
+
+	status := models.InvitationDeclined
+	var member *models.TripMember
+	if accept {
+		status = models.InvitationAccepted
+		member = &models.TripMember{
+			TripID:  invitation.TripID,
+			UserID:  invitation.InviteeID,
+			Role:    invitation.Role,
+			AddedBy: invitation.InviterID,
+			AddedAt: now,
+		}
+	}
+	// The member is only added if the invitation is still pending
+	if err := s.InvitationRepo.Respond(invitationID, status, now, member); err != nil {
+		if errors.Is(err, db.ErrInvitationNotPending) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		return nil, fmt.Errorf("failed to respond to invitation: %w", err)
+	}
+	invitation.Status = status
+	invitation.RespondedAt = &now
+	if !accept {
+		return invitation, nil
+	}
+
+	if s.Events != nil {
+		evt := events.TripInvitationAcceptedEvent{
+			InvitationID: invitation.InvitationID,
+			TripID:       invitation.TripID,
+			InviterID:    invitation.InviterID,
+			InviteeID:    invitation.InviteeID,
+			AcceptedAt:   now,
+		}
+		_ = s.Events.Publish("trip.invitation.accepted", evt)
+	}
+
+	return invitation, nil
+}
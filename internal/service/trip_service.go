@@ -1,25 +1,37 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"main/internal/db"
 	"main/internal/events"
 	"main/internal/models"
 	"strconv"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type TripService struct {
-	TripRepo *db.TripsRepository
-	Events   *events.Publisher
+	TripRepo     *db.TripsRepository
+	MemberRepo   *db.TripMembersRepository
+	TagRepo      *db.TripTagsRepository
+	RevisionRepo *db.TripRevisionsRepository
+	MediaRepo    *db.MediaRepository
+	Minio        *MinioService
+	Events       *events.Publisher
 }
 
 func (s *TripService) CreateTrip(trip models.Trip) (any, error) {
-	fmt.Printf("Creating new trip:", trip)
+	fmt.Printf("Creating new trip: %+v\n", trip)
+
+	if trip.Language != "" && !models.SearchLanguages[trip.Language] {
+		return nil, fmt.Errorf("%w: unsupported language %q", ErrInvalidInput, trip.Language)
+	}
 
 	result, err := s.TripRepo.CreateTrip(trip)
 	if err != nil {
-		fmt.Printf("Error creating trip:", err)
+		fmt.Printf("Error creating trip: %v\n", err)
 		return nil, err
 	}
 
@@ -32,13 +44,51 @@ func (s *TripService) CreateTrip(trip models.Trip) (any, error) {
 		_ = s.Events.Publish("trip.created", evt)
 	}
 
-	fmt.Printf("Successfully created trip. Result:", result)
+	fmt.Printf("Successfully created trip. Result: %+v\n", result)
 	return result, nil
 }
 
-func (s *TripService) UpdateTrip(trip models.Trip) (any, error) {
-	// Convert string ID to integer
-	result, err := s.TripRepo.UpdateTrip(trip)
+// UpdateTrip fails with ErrVersionConflict when version is set and the trip
+// has changed since.
+func (s *TripService) UpdateTrip(trip models.Trip, userID uint, version int) (any, error) {
+	existing, err := s.TripRepo.GetTripByID(trip.TripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if trip.Language != "" && !models.SearchLanguages[trip.Language] {
+		return nil, fmt.Errorf("%w: unsupported language %q", ErrInvalidInput, trip.Language)
+	}
+
+	role, err := s.GetUserRole(existing, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanEdit() {
+		return nil, ErrNotAuthorized
+	}
+	if version != 0 && existing.Version != version {
+		return nil, ErrVersionConflict
+	}
+	// Only owners decide who can see the trip
+	if trip.Visibility != "" && trip.Visibility != existing.Visibility && role != models.RoleOwner {
+		return nil, ErrNotAuthorized
+	}
+	trip.UserID = existing.UserID
+
+	// The dates left out of the update keep their stored value
+	startDate, endDate := existing.StartDate, existing.EndDate
+	if trip.StartDate != nil {
+		startDate = trip.StartDate
+	}
+	if trip.EndDate != nil {
+		endDate = trip.EndDate
+	}
+	if startDate != nil && endDate != nil && endDate.Before(startDate.Time) {
+		return nil, fmt.Errorf("%w: start_date must not be after end_date", ErrInvalidInput)
+	}
+
+	result, err := s.TripRepo.UpdateTrip(trip, version, s.newRevision(userID, nil))
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +105,61 @@ func (s *TripService) UpdateTrip(trip models.Trip) (any, error) {
 	return result, nil
 }
 
-func (s *TripService) DeleteTrip(tripID string) error {
+// PatchTrip applies a JSON merge patch to the trip, following the same
+// permissions as UpdateTrip.
+func (s *TripService) PatchTrip(tripID int, userID uint, patch models.TripPatch, version int) (models.Trip, error) {
+	existing, err := s.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	role, err := s.GetUserRole(existing, userID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	if !role.CanEdit() {
+		return models.Trip{}, ErrNotAuthorized
+	}
+	if version != 0 && existing.Version != version {
+		return models.Trip{}, ErrVersionConflict
+	}
+
+	before, err := s.withTags([]models.Trip{existing})
+	if err != nil {
+		return models.Trip{}, err
+	}
+	patched := before[0]
+	fields, tagsChanged, err := patch.Apply(&patched)
+	if err != nil {
+		return models.Trip{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	// Only owners decide who can see the trip
+	if patched.Visibility != existing.Visibility && role != models.RoleOwner {
+		return models.Trip{}, ErrNotAuthorized
+	}
+
+	var tags []string
+	if tagsChanged {
+		tags = patched.Tags
+	}
+	if err := s.TripRepo.UpdateTripFields(tripID, fields, tags, version, s.newRevision(userID, nil)); err != nil {
+		return models.Trip{}, err
+	}
+
+	if s.Events != nil {
+		evt := events.TripUpdatedEvent{
+			TripID:    tripID,
+			OwnerID:   existing.UserID,
+			UpdatedAt: time.Now(),
+		}
+		_ = s.Events.Publish("trip.updated", evt)
+	}
+
+	return s.GetTripDetails(strconv.Itoa(tripID), userID)
+}
+
+// DeleteTrip moves the trip to the trash, and its media too when deleteMedia
+// is set.
+func (s *TripService) DeleteTrip(tripID string, userID uint, deleteMedia bool) error {
 	// Convert string ID to integer
 	id, err := strconv.Atoi(tripID)
 	if err != nil {
@@ -67,7 +171,15 @@ func (s *TripService) DeleteTrip(tripID string) error {
 		return err
 	}
 
-	err = s.TripRepo.DeleteTrip(id)
+	role, err := s.GetUserRole(trip, userID)
+	if err != nil {
+		return err
+	}
+	if role != models.RoleOwner {
+		return ErrNotAuthorized
+	}
+
+	err = s.TripRepo.DeleteTrip(id, deleteMedia)
 	if err != nil {
 		return err
 	}
@@ -84,6 +196,69 @@ func (s *TripService) DeleteTrip(tripID string) error {
 	return nil
 }
 
+// CloneTrip copies a trip the user can see into a new trip they own. Album
+// links are only copied from the user's own trips, since the albums are theirs.
+func (s *TripService) CloneTrip(tripID int, userID uint, opts models.TripCloneOptions) (models.Trip, error) {
+	source, err := s.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	canView, err := s.CanViewTrip(source, userID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	if !canView {
+		return models.Trip{}, ErrNotAuthorized
+	}
+
+	clone := models.Trip{
+		UserID:      userID,
+		Name:        source.Name,
+		Description: source.Description,
+		Visibility:  source.Visibility,
+		StartDate:   source.StartDate,
+		EndDate:     source.EndDate,
+		Language:    source.Language,
+		Category:    source.Category,
+	}
+	if opts.Name != "" {
+		clone.Name = opts.Name
+	}
+
+	dayOffset := 0
+	if opts.StartDate != nil {
+		if source.StartDate == nil {
+			return models.Trip{}, fmt.Errorf("%w: the trip has no start_date to shift from", ErrInvalidInput)
+		}
+		dayOffset = int(opts.StartDate.Sub(source.StartDate.Time).Hours() / 24)
+		clone.StartDate = opts.StartDate
+		if source.EndDate != nil {
+			endDate := models.NewDate(source.EndDate.AddDate(0, 0, dayOffset))
+			clone.EndDate = &endDate
+		}
+	}
+
+	copyAlbums := source.UserID == userID
+	if err := s.TripRepo.CloneTrip(tripID, &clone, opts, dayOffset, copyAlbums); err != nil {
+		return models.Trip{}, fmt.Errorf("failed to clone trip: %w", err)
+	}
+
+	if s.Events != nil {
+		evt := events.TripCreatedEvent{
+			TripID:    clone.TripID,
+			OwnerID:   userID,
+			CreatedAt: time.Now(),
+		}
+		_ = s.Events.Publish("trip.created", evt)
+	}
+
+	trips, err := s.withDetails([]models.Trip{clone}, userID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	return trips[0], nil
+}
+
 func (s *TripService) GetTripByID(tripID string) (models.Trip, error) {
 	// Convert string ID to integer
 	id, err := strconv.Atoi(tripID)
@@ -91,30 +266,68 @@ func (s *TripService) GetTripByID(tripID string) (models.Trip, error) {
 		return models.Trip{}, err
 	}
 
-	trip, err := s.TripRepo.GetTripByID(id)
+	return s.TripRepo.GetTripByID(id)
+}
+
+// AnonymousViewer stands for unauthenticated callers, who only see PUBLIC
+// media.
+const AnonymousViewer uint = 0
+
+// GetTripDetails is GetTripByID with the trip's tags and cover resolved, for
+// responses that return the trip to viewerID.
+func (s *TripService) GetTripDetails(tripID string, viewerID uint) (models.Trip, error) {
+	trip, err := s.GetTripByID(tripID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+
+	trips, err := s.withDetails([]models.Trip{trip}, viewerID)
 	if err != nil {
 		return models.Trip{}, err
 	}
 
-	return trip, nil
+	return trips[0], nil
 }
 
-func (s *TripService) GetAllTrips() ([]models.Trip, error) {
-	trips, err := s.TripRepo.GetAllTrips()
+// GetTripsByIDs returns the trips found among tripIDs, in the order given,
+// with their details resolved for viewerID in one batch.
+func (s *TripService) GetTripsByIDs(tripIDs []uint, viewerID uint) ([]models.Trip, error) {
+	found, err := s.TripRepo.GetTripsByIDs(tripIDs)
 	if err != nil {
 		return nil, err
 	}
+	byID := make(map[int]models.Trip, len(found))
+	for _, trip := range found {
+		byID[trip.TripID] = trip
+	}
 
-	return trips, nil
+	trips := make([]models.Trip, 0, len(found))
+	for _, id := range tripIDs {
+		if trip, ok := byID[int(id)]; ok {
+			trips = append(trips, trip)
+		}
+	}
+	return s.withDetails(trips, viewerID)
 }
 
-func (s *TripService) GetMyTrips(userID uint) ([]models.Trip, error) {
-	trips, err := s.TripRepo.GetMyTrips(userID)
+func (s *TripService) GetAllTrips(page models.PageRequest) ([]models.Trip, string, error) {
+	trips, next, err := s.TripRepo.GetAllTrips(page)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	trips, err = s.withDetails(trips, AnonymousViewer)
+	return trips, next, err
+}
+
+func (s *TripService) GetMyTrips(userID uint, page models.PageRequest) ([]models.Trip, string, error) {
+	trips, next, err := s.TripRepo.GetMyTrips(userID, page)
+	if err != nil {
+		return nil, "", err
 
 	}
-	return trips, nil
+	trips, err = s.withDetails(trips, userID)
+	return trips, next, err
 }
 
 func (s *TripService) GetAllPublicTrips() ([]models.Trip, error) {
@@ -125,8 +338,13 @@ func (s *TripService) GetAllPublicTrips() ([]models.Trip, error) {
 	return trips, nil
 }
 
-func (s *TripService) GetPublicTripsForEveryone(userID uint) ([]models.Trip, error) {
-	return s.TripRepo.GetPublicTripsForEveryone(userID)
+func (s *TripService) GetPublicTripsForEveryone(userID uint, page models.PageRequest) ([]models.Trip, string, error) {
+	trips, next, err := s.TripRepo.GetPublicTripsForEveryone(userID, page)
+	if err != nil {
+		return nil, "", err
+	}
+	trips, err = s.withDetails(trips, userID)
+	return trips, next, err
 }
 
 func (s *TripService) GetPublicTripsForUser(userID uint) ([]models.Trip, error) {
@@ -137,16 +355,485 @@ func (s *TripService) GetPublicAndFriendsTripsForUser(userID uint) ([]models.Tri
 	return s.TripRepo.GetPublicAndFriendsTripsForUser(userID)
 }
 
-func (s *TripService) GetTripsByUserID(userID string) ([]models.Trip, error) {
+func (s *TripService) GetTripsByUserID(userID string, page models.PageRequest) ([]models.Trip, string, error) {
 
 	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	trips, next, err := s.TripRepo.GetTripsByUserID(uint(id), page)
+	if err != nil {
+		return nil, "", err
+	}
+	trips, err = s.withDetails(trips, AnonymousViewer)
+	return trips, next, err
+}
+
+func (s *TripService) GetFollowedUsersTrips(viewerID uint, followedIDs []uint, friendIDs []uint, page models.PageRequest) ([]models.Trip, string, error) {
+	trips, next, err := s.TripRepo.GetFollowedUsersTrips(viewerID, followedIDs, friendIDs, page)
+	if err != nil {
+		return nil, "", err
+	}
+	trips, err = s.withDetails(trips, viewerID)
+	return trips, next, err
+}
+
+func (s *TripService) SearchTrips(filters models.TripSearchFilters, userID uint) ([]models.TripSearchResult, error) {
+	if filters.Language != "" && !models.SearchLanguages[filters.Language] {
+		return nil, fmt.Errorf("%w: unsupported language %q", ErrInvalidInput, filters.Language)
+	}
+	if filters.Visibility != "" {
+		switch models.VisibilityEnum(filters.Visibility) {
+		case models.Public, models.Friends, models.Private:
+		default:
+			return nil, fmt.Errorf("%w: visibility must be PUBLIC, FRIENDS or PRIVATE", ErrInvalidInput)
+		}
+	}
+	if filters.Limit <= 0 || filters.Limit > models.MaxPageLimit {
+		filters.Limit = models.DefaultPageLimit
+	}
+
+	results, err := s.TripRepo.SearchTrips(filters, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	trips := make([]models.Trip, len(results))
+	for i := range results {
+		trips[i] = results[i].Trip
+	}
+	trips, err = s.withDetails(trips, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Trip = trips[i]
+	}
+	return results, nil
+}
+
+func (s *TripService) GetPopularTags(limit int) ([]models.TagCount, error) {
+	if limit <= 0 || limit > models.MaxPageLimit {
+		limit = models.DefaultPageLimit
+	}
+	return s.TagRepo.GetPopularTags(limit)
+}
+
+func (s *TripService) GetPublicTripsByTag(tag string, userID uint, page models.PageRequest) ([]models.Trip, string, error) {
+	trips, next, err := s.TagRepo.GetPublicTripsByTag(models.NormalizeTag(tag), userID, page)
+	if err != nil {
+		return nil, "", err
+	}
+	trips, err = s.withDetails(trips, userID)
+	return trips, next, err
+}
+
+// newRevision starts the revision recording an update by userID, or returns
+// nil when revisions are not kept.
+func (s *TripService) newRevision(userID uint, revertOf *int) *models.TripRevision {
+	if s.RevisionRepo == nil {
+		return nil
+	}
+	return &models.TripRevision{
+		UserID:    userID,
+		CreatedAt: time.Now(),
+		RevertOf:  revertOf,
+	}
+}
+
+// GetHistory lists the revisions of a trip, newest first. Only members see
+// who changed what.
+func (s *TripService) GetHistory(tripID int, userID uint) ([]models.TripRevision, error) {
+	trip, err := s.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.GetUserRole(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, ErrNotAuthorized
+	}
+	return s.RevisionRepo.GetRevisionsByTripID(tripID)
+}
+
+// RevertRevision undoes one revision by setting the fields it changed back to
+// their previous values. The revert is recorded as a revision of its own. It
+// fails with ErrVersionConflict when version is set and the trip has changed
+// since.
+func (s *TripService) RevertRevision(tripID int, revisionNumber int, userID uint, version int) (models.Trip, error) {
+	existing, err := s.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	role, err := s.GetUserRole(existing, userID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	if !role.CanEdit() {
+		return models.Trip{}, ErrNotAuthorized
+	}
+	if version != 0 && existing.Version != version {
+		return models.Trip{}, ErrVersionConflict
+	}
+
+	revision, err := s.RevisionRepo.GetRevision(tripID, revisionNumber)
+	if err != nil {
+		return models.Trip{}, err
+	}
+
+	before, err := s.withTags([]models.Trip{existing})
+	if err != nil {
+		return models.Trip{}, err
+	}
+	reverted := before[0]
+	fields := make(map[string]any)
+	revertTags := false
+	for _, change := range revision.Changes {
+		if err := change.ApplyBefore(&reverted); err != nil {
+			return models.Trip{}, fmt.Errorf("failed to read revision %d: %w", revisionNumber, err)
+		}
+		switch change.Field {
+		case "tags":
+			revertTags = true
+		case "visibility":
+			if role != models.RoleOwner {
+				return models.Trip{}, ErrNotAuthorized
+			}
+			fields["visibility"] = reverted.Visibility
+		case "name":
+			fields["name"] = reverted.Name
+		case "description":
+			fields["description"] = reverted.Description
+		case "start_date":
+			fields["start_date"] = reverted.StartDate
+		case "end_date":
+			fields["end_date"] = reverted.EndDate
+		case "language":
+			fields["language"] = reverted.Language
+		case "category":
+			fields["category"] = reverted.Category
+		}
+	}
+
+	// Later revisions may have moved the other date
+	if reverted.StartDate != nil && reverted.EndDate != nil && reverted.EndDate.Before(reverted.StartDate.Time) {
+		return models.Trip{}, fmt.Errorf("%w: reverting would put start_date after end_date", ErrInvalidInput)
+	}
+
+	var tags []string
+	if revertTags {
+		tags = reverted.Tags
+	}
+	if err := s.TripRepo.UpdateTripFields(tripID, fields, tags, version, s.newRevision(userID, &revision.Revision)); err != nil {
+		return models.Trip{}, fmt.Errorf("failed to revert trip: %w", err)
+	}
+
+	if s.Events != nil {
+		evt := events.TripUpdatedEvent{
+			TripID:    tripID,
+			OwnerID:   existing.UserID,
+			UpdatedAt: time.Now(),
+		}
+		_ = s.Events.Publish("trip.updated", evt)
+	}
+
+	return s.GetTripDetails(strconv.Itoa(tripID), userID)
+}
+
+// withDetails fills in the fields of the trips that live outside trips.trips,
+// as viewerID is allowed to see them.
+func (s *TripService) withDetails(trips []models.Trip, viewerID uint) ([]models.Trip, error) {
+	trips, err := s.withTags(trips)
 	if err != nil {
 		return nil, err
 	}
+	return s.withCovers(trips, viewerID)
+}
 
-	return s.TripRepo.GetTripsByUserID(uint(id))
+// withTags loads the tags of all the trips in a single query.
+func (s *TripService) withTags(trips []models.Trip) ([]models.Trip, error) {
+	if s.TagRepo == nil || len(trips) == 0 {
+		return trips, nil
+	}
+
+	tripIDs := make([]int, len(trips))
+	for i, trip := range trips {
+		tripIDs[i] = trip.TripID
+	}
+	tags, err := s.TagRepo.GetTagsByTripIDs(tripIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip tags: %w", err)
+	}
+
+	for i := range trips {
+		trips[i].Tags = tags[trips[i].TripID]
+		if trips[i].Tags == nil {
+			trips[i].Tags = []string{}
+		}
+	}
+	return trips, nil
+}
+
+// withCovers resolves the cover URL of every trip for viewerID. A chosen cover
+// that is no longer visible enough for its trip, or that the viewer may not
+// see, falls back to the automatic pick among the media they can see.
+func (s *TripService) withCovers(trips []models.Trip, viewerID uint) ([]models.Trip, error) {
+	if s.MediaRepo == nil || s.Minio == nil || len(trips) == 0 {
+		return trips, nil
+	}
+
+	var tripIDs []int
+	var coverIDs []int64
+	for _, trip := range trips {
+		tripIDs = append(tripIDs, trip.TripID)
+		if trip.CoverMediaID != nil {
+			coverIDs = append(coverIDs, *trip.CoverMediaID)
+		}
+	}
+
+	chosen, err := s.MediaRepo.GetMediaByIDs(coverIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip covers: %w", err)
+	}
+	chosenByID := make(map[int64]models.Media)
+	for _, media := range chosen {
+		chosenByID[media.MediaID] = media
+	}
+	automatic, err := s.MediaRepo.GetAutomaticCovers(tripIDs, int64(viewerID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip covers: %w", err)
+	}
+
+	for i, trip := range trips {
+		cover, ok := automatic[trip.TripID]
+		if trip.CoverMediaID != nil {
+			if media, found := chosenByID[*trip.CoverMediaID]; found && validCover(trip, media) && canViewMedia(s.MediaRepo, &media, int64(viewerID)) {
+				cover, ok = media, true
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		url, err := s.Minio.GetPresignedURL(cover.FilePath, time.Minute*5)
+		if err != nil {
+			fmt.Printf("Error: Failed to sign cover of trip %d - %v\n", trip.TripID, err)
+			continue
+		}
+		trips[i].CoverURL = url
+	}
+	return trips, nil
 }
 
-func (s *TripService) SearchTrips(query string, userID uint) ([]models.Trip, error) {
-	return s.TripRepo.SearchTrips(query, userID)
+// SetCover lets the owner pick the trip's cover, or go back to the automatic
+// pick with a nil mediaID. It fails with ErrVersionConflict when version is
+// set and the trip has changed since.
+func (s *TripService) SetCover(tripID int, userID uint, mediaID *int64, version int) (models.Trip, error) {
+	trip, err := s.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	role, err := s.GetUserRole(trip, userID)
+	if err != nil {
+		return models.Trip{}, err
+	}
+	if role != models.RoleOwner {
+		return models.Trip{}, ErrNotAuthorized
+	}
+	if version != 0 && trip.Version != version {
+		return models.Trip{}, ErrVersionConflict
+	}
+
+	if mediaID != nil {
+		media, err := s.MediaRepo.GetMediaByID(*mediaID)
+		if err != nil {
+			return models.Trip{}, fmt.Errorf("%w: media %d does not exist", ErrInvalidInput, *mediaID)
+		}
+		if int(media.TripID) != tripID {
+			return models.Trip{}, fmt.Errorf("%w: media does not belong to this trip", ErrInvalidInput)
+		}
+		if media.Type != "photo" {
+			return models.Trip{}, fmt.Errorf("%w: only photos can be used as a cover", ErrInvalidInput)
+		}
+		if !validCover(trip, *media) {
+			return models.Trip{}, fmt.Errorf("%w: a %s trip cannot use %s media as its cover",
+				ErrInvalidInput, trip.Visibility, media.Visibility)
+		}
+	}
+
+	if err := s.TripRepo.SetCoverMedia(tripID, mediaID, version); err != nil {
+		return models.Trip{}, fmt.Errorf("failed to set trip cover: %w", err)
+	}
+
+	return s.GetTripDetails(strconv.Itoa(tripID), userID)
+}
+
+// validCover keeps covers from showing media to viewers who could see the
+// trip but not the media itself.
+func validCover(trip models.Trip, media models.Media) bool {
+	return media.Visibility.AtLeastAsOpenAs(models.VisibilityEnum(trip.Visibility))
+}
+
+// GetUserRole returns the user's role on the trip, or an empty role when the
+// user is not a member.
+func (s *TripService) GetUserRole(trip models.Trip, userID uint) (models.TripRole, error) {
+	if trip.UserID == userID {
+		return models.RoleOwner, nil
+	}
+	if s.MemberRepo == nil {
+		return "", nil
+	}
+	member, err := s.MemberRepo.GetMember(trip.TripID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get trip member: %w", err)
+	}
+	return member.Role, nil
+}
+
+// CanViewTrip applies the trip's visibility to the given viewer. Members can
+// always see the trips they belong to.
+func (s *TripService) CanViewTrip(trip models.Trip, userID uint) (bool, error) {
+	role, err := s.GetUserRole(trip, userID)
+	if err != nil {
+		return false, err
+	}
+	if role != "" {
+		return true, nil
+	}
+	switch models.VisibilityEnum(trip.Visibility) {
+	case models.Public:
+		return true, nil
+	case models.Friends:
+		return s.AreFriends(trip.UserID, userID), nil
+	}
+	return false, nil
+}
+
+// AreFriends checks the friendships shared with the media service.
+func (s *TripService) AreFriends(userID1, userID2 uint) bool {
+	return s.MediaRepo.AreFriends(int64(userID1), int64(userID2))
+}
+
+// CanEditTrip reports whether the user may modify the trip and its sub-resources.
+func (s *TripService) CanEditTrip(trip models.Trip, userID uint) (bool, error) {
+	role, err := s.GetUserRole(trip, userID)
+	if err != nil {
+		return false, err
+	}
+	return role.CanEdit(), nil
+}
+
+// Participants are the trip's creator and members, whatever their role.
+func (s *TripService) Participants(trip models.Trip) (map[uint]bool, error) {
+	members, err := s.MemberRepo.GetMembersByTripID(trip.TripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip members: %w", err)
+	}
+	participants := map[uint]bool{trip.UserID: true}
+	for _, member := range members {
+		participants[member.UserID] = true
+	}
+	return participants, nil
+}
+
+func (s *TripService) GetSharedTrips(userID uint) ([]models.Trip, error) {
+	trips, err := s.MemberRepo.GetTripsByMember(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withDetails(trips, userID)
+}
+
+func (s *TripService) GetMembers(tripID int, userID uint) ([]models.TripMember, error) {
+	trip, err := s.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.CanViewTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+
+	members, err := s.MemberRepo.GetMembersByTripID(tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The creator has no membership row, so list them first
+	return append([]models.TripMember{{
+		TripID: trip.TripID,
+		UserID: trip.UserID,
+		Role:   models.RoleOwner,
+	}}, members...), nil
+}
+
+// AddMember grants memberUserID the given role, or changes the role of an
+// existing member. Only owners manage members.
+func (s *TripService) AddMember(tripID int, userID uint, memberUserID uint, role models.TripRole) (*models.TripMember, error) {
+	if !role.IsValid() {
+		return nil, fmt.Errorf("%w: role must be one of owner, editor or viewer", ErrInvalidInput)
+	}
+
+	trip, err := s.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	callerRole, err := s.GetUserRole(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if callerRole != models.RoleOwner {
+		return nil, ErrNotAuthorized
+	}
+	if memberUserID == trip.UserID {
+		return nil, fmt.Errorf("%w: the trip creator is already an owner", ErrInvalidInput)
+	}
+
+	member := &models.TripMember{
+		TripID:  tripID,
+		UserID:  memberUserID,
+		Role:    role,
+		AddedBy: userID,
+		AddedAt: time.Now(),
+	}
+	if err := s.MemberRepo.SaveMember(member); err != nil {
+		return nil, fmt.Errorf("failed to save member: %w", err)
+	}
+
+	return member, nil
+}
+
+// RemoveMember lets owners remove anyone and lets members leave on their own.
+func (s *TripService) RemoveMember(tripID int, userID uint, memberUserID uint) error {
+	trip, err := s.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return err
+	}
+	if memberUserID != userID {
+		role, err := s.GetUserRole(trip, userID)
+		if err != nil {
+			return err
+		}
+		if role != models.RoleOwner {
+			return ErrNotAuthorized
+		}
+	}
+	if memberUserID == trip.UserID {
+		return fmt.Errorf("%w: the trip creator cannot be removed", ErrInvalidInput)
+	}
+
+	if _, err := s.MemberRepo.GetMember(tripID, memberUserID); err != nil {
+		return err
+	}
+
+	return s.MemberRepo.DeleteMember(tripID, memberUserID)
 }
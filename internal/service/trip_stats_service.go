@@ -0,0 +1,253 @@
+package service
+
+import (
+	"main/internal/models"
+	"math"
+	"sort"
+	"time"
+)
+
+const earthRadiusKm = 6371.0
+
+type TripStatsService struct {
+	TripService  *TripService
+	MediaService *MediaService
+}
+
+func (s *TripStatsService) GetTripStats(tripID int, userID uint) (*models.TripStats, error) {
+	trip, err := s.TripService.TripRepo.GetTripByID(tripID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.TripService.CanViewTrip(trip, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotAuthorized
+	}
+
+	media, err := s.MediaService.GetMediaDataByTripID(int64(tripID), int64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := s.MediaService.LocationsOf(media)
+	if err != nil {
+		return nil, err
+	}
+
+	return computeTripStats(trip, media, locations), nil
+}
+
+// GetUserStats aggregates the trips of userID visible to viewerID: their own
+// trips when they are the same user, PUBLIC and FRIENDS trips for friends and
+// only PUBLIC trips for everyone else.
+func (s *TripStatsService) GetUserStats(userID uint, viewerID uint, friends bool) (*models.UserStats, error) {
+	visibilities := []string{string(models.Public)}
+	switch {
+	case userID == viewerID:
+		visibilities = append(visibilities, string(models.Friends), string(models.Private))
+	case friends:
+		visibilities = append(visibilities, string(models.Friends))
+	}
+
+	trips, err := s.TripService.TripRepo.GetUserTripsWithVisibility(userID, visibilities)
+	if err != nil {
+		return nil, err
+	}
+
+	tripIDs := make([]int, len(trips))
+	for i, trip := range trips {
+		tripIDs[i] = trip.TripID
+	}
+	media, err := s.MediaService.GetMediaDataByTripIDs(tripIDs, int64(viewerID))
+	if err != nil {
+		return nil, err
+	}
+	locations, err := s.MediaService.LocationsOf(media)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaByTrip := make(map[int][]models.Media)
+	for _, m := range media {
+		mediaByTrip[int(m.TripID)] = append(mediaByTrip[int(m.TripID)], m)
+	}
+
+	stats := &models.UserStats{
+		UserID:        userID,
+		TripCount:     len(trips),
+		TripsPerYear:  make(map[int]int),
+		CountryVisits: []models.CountryVisit{},
+	}
+	countries := make(map[string]bool)
+	cities := make(map[string]bool)
+	firstVisits := make(map[string]models.Date)
+	for _, trip := range trips {
+		tripStats := computeTripStats(trip, mediaByTrip[trip.TripID], locations)
+
+		stats.TotalDistanceKm += tripStats.DistanceKm
+		for _, country := range tripStats.Countries {
+			countries[country] = true
+		}
+		for _, city := range tripStats.Cities {
+			cities[city] = true
+		}
+
+		switch {
+		case trip.StartDate != nil:
+			stats.TripsPerYear[trip.StartDate.Year()]++
+		case tripStats.FirstCapture != nil:
+			stats.TripsPerYear[tripStats.FirstCapture.Year()]++
+		}
+
+		if tripStats.DurationDays > 0 && (stats.LongestTrip == nil || tripStats.DurationDays > stats.LongestTrip.DurationDays) {
+			stats.LongestTrip = &models.TripDuration{
+				TripID:       trip.TripID,
+				Name:         trip.Name,
+				DurationDays: tripStats.DurationDays,
+			}
+		}
+
+		for _, m := range mediaByTrip[trip.TripID] {
+			location, ok := locations[m.LocationID]
+			if !ok || location.Country == "" {
+				continue
+			}
+			var visit models.Date
+			switch {
+			case !m.CaptureDate.IsZero():
+				visit = models.NewDate(m.CaptureDate)
+			case trip.StartDate != nil:
+				visit = *trip.StartDate
+			default:
+				continue
+			}
+			if first, seen := firstVisits[location.Country]; !seen || visit.Before(first.Time) {
+				firstVisits[location.Country] = visit
+			}
+		}
+	}
+
+	stats.TotalCountries = len(countries)
+	stats.TotalCities = len(cities)
+	stats.TotalDistanceKm = math.Round(stats.TotalDistanceKm*10) / 10
+	for _, country := range sortedKeys(countries) {
+		visit := models.CountryVisit{Country: country}
+		if first, ok := firstVisits[country]; ok {
+			visit.FirstVisit = &first
+		}
+		stats.CountryVisits = append(stats.CountryVisits, visit)
+	}
+
+	return stats, nil
+}
+
+func computeTripStats(trip models.Trip, media []models.Media, locations map[int64]models.Location) *models.TripStats {
+	stats := &models.TripStats{
+		TripID:    trip.TripID,
+		Countries: []string{},
+		Cities:    []string{},
+	}
+
+	// Walk the media in the order they were taken
+	sortByCaptureDate(media)
+
+	countries := make(map[string]bool)
+	cities := make(map[string]bool)
+	var previous *models.Media
+	for i := range media {
+		m := &media[i]
+
+		switch m.Type {
+		case "photo":
+			stats.PhotoCount++
+		case "video":
+			stats.VideoCount++
+		}
+
+		if !m.CaptureDate.IsZero() {
+			if stats.FirstCapture == nil {
+				stats.FirstCapture = &m.CaptureDate
+			}
+			stats.LastCapture = &m.CaptureDate
+		}
+
+		if location, ok := locations[m.LocationID]; ok {
+			if location.Country != "" {
+				countries[location.Country] = true
+			}
+			if location.City != "" {
+				cities[location.City] = true
+			}
+		}
+
+		if !hasGPS(*m) {
+			continue
+		}
+		if previous != nil {
+			stats.DistanceKm += haversineKm(previous.GpsLatitude, previous.GpsLongitude, m.GpsLatitude, m.GpsLongitude)
+		}
+		previous = m
+
+		altitude := m.GpsAltitude
+		if stats.MinAltitude == nil || altitude < *stats.MinAltitude {
+			stats.MinAltitude = &altitude
+		}
+		if stats.MaxAltitude == nil || altitude > *stats.MaxAltitude {
+			stats.MaxAltitude = &altitude
+		}
+	}
+	stats.DistanceKm = math.Round(stats.DistanceKm*10) / 10
+
+	stats.Countries = sortedKeys(countries)
+	stats.Cities = sortedKeys(cities)
+
+	// The planned dates win over the capture dates when the trip has them
+	switch {
+	case trip.StartDate != nil && trip.EndDate != nil:
+		stats.DurationDays = daysBetween(trip.StartDate.Time, trip.EndDate.Time) + 1
+	case stats.FirstCapture != nil:
+		stats.DurationDays = daysBetween(models.NewDate(*stats.FirstCapture).Time, models.NewDate(*stats.LastCapture).Time) + 1
+	}
+
+	return stats
+}
+
+// sortByCaptureDate orders media as they were taken, by ID on ties.
+func sortByCaptureDate(media []models.Media) {
+	sort.SliceStable(media, func(i, j int) bool {
+		if media[i].CaptureDate.Equal(media[j].CaptureDate) {
+			return media[i].MediaID < media[j].MediaID
+		}
+		return media[i].CaptureDate.Before(media[j].CaptureDate)
+	})
+}
+
+func hasGPS(m models.Media) bool {
+	return m.GpsLatitude != 0 || m.GpsLongitude != 0
+}
+
+// haversineKm returns the great-circle distance between two coordinates.
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
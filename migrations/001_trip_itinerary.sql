@@ -0,0 +1,24 @@
+-- Itinerary days and their ordered stops
+CREATE TABLE IF NOT EXISTS trips.itinerary_days (
+    day_id  BIGSERIAL PRIMARY KEY,
+    trip_id INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    date    DATE NOT NULL,
+    title   VARCHAR(255),
+    notes   TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_itinerary_days_trip ON trips.itinerary_days (trip_id, date);
+
+CREATE TABLE IF NOT EXISTS trips.itinerary_stops (
+    stop_id       BIGSERIAL PRIMARY KEY,
+    day_id        BIGINT NOT NULL REFERENCES trips.itinerary_days (day_id) ON DELETE CASCADE,
+    trip_id       INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    position      INTEGER NOT NULL,
+    name          VARCHAR(255) NOT NULL,
+    location_id   BIGINT REFERENCES locations.locations (location_id),
+    planned_start TIMESTAMPTZ,
+    planned_end   TIMESTAMPTZ,
+    notes         TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_itinerary_stops_day ON trips.itinerary_stops (day_id, position);
@@ -0,0 +1,11 @@
+-- Collaborators on a trip; the creator in trips.trips.user_id is the implicit owner
+CREATE TABLE IF NOT EXISTS trips.trip_members (
+    trip_id  INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    user_id  INTEGER NOT NULL,
+    role     VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    added_by INTEGER NOT NULL,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (trip_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_members_user ON trips.trip_members (user_id);
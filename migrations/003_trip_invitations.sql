@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS trips.trip_invitations (
+    invitation_id BIGSERIAL PRIMARY KEY,
+    trip_id       INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    inviter_id    INTEGER NOT NULL,
+    invitee_id    INTEGER NOT NULL,
+    role          VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    status        VARCHAR(16) NOT NULL DEFAULT 'pending'
+                  CHECK (status IN ('pending', 'accepted', 'declined', 'expired')),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at    TIMESTAMPTZ NOT NULL,
+    responded_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_invitations_invitee ON trips.trip_invitations (invitee_id, status);
@@ -0,0 +1,5 @@
+-- Keyset pagination indexes for the trip list endpoints
+CREATE INDEX IF NOT EXISTS idx_trips_user_id ON trips.trips (user_id, trip_id);
+CREATE INDEX IF NOT EXISTS idx_trips_visibility ON trips.trips (visibility, trip_id);
+CREATE INDEX IF NOT EXISTS idx_trips_start_date ON trips.trips ((COALESCE(start_date, '')), trip_id);
+CREATE INDEX IF NOT EXISTS idx_trips_name ON trips.trips (name, trip_id);
@@ -0,0 +1,57 @@
+-- Full-text search over trips. Trip name and description are stemmed with the
+-- trip's language; city and country names of its PUBLIC media are indexed
+-- verbatim so a trip can be found by the places it visited.
+ALTER TABLE trips.trips ADD COLUMN IF NOT EXISTS language regconfig NOT NULL DEFAULT 'english';
+ALTER TABLE trips.trips ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION trips.trip_places(p_trip_id INTEGER) RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(DISTINCT concat_ws(' ', l.city, l.country), ' '), '')
+    FROM media.media m
+    JOIN locations.locations l ON l.location_id = m.location_id
+    WHERE m.trip_id = p_trip_id AND m.visibility = 'PUBLIC'
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION trips.trip_search_vector(p_language regconfig, p_name TEXT, p_description TEXT, p_places TEXT)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector(p_language, COALESCE(p_name, '')), 'A')
+        || setweight(to_tsvector(p_language, COALESCE(p_description, '')), 'B')
+        || setweight(to_tsvector('simple', COALESCE(p_places, '')), 'C')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION trips.trips_search_trigger() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := trips.trip_search_vector(NEW.language, NEW.name, NEW.description, trips.trip_places(NEW.trip_id));
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trips_search_update ON trips.trips;
+CREATE TRIGGER trips_search_update
+    BEFORE INSERT OR UPDATE OF name, description, language ON trips.trips
+    FOR EACH ROW EXECUTE FUNCTION trips.trips_search_trigger();
+
+CREATE OR REPLACE FUNCTION trips.media_search_trigger() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE trips.trips
+        SET search_vector = trips.trip_search_vector(language, name, description, trips.trip_places(trip_id))
+        WHERE trip_id = OLD.trip_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE trips.trips
+        SET search_vector = trips.trip_search_vector(language, name, description, trips.trip_places(trip_id))
+        WHERE trip_id = NEW.trip_id;
+    END IF;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS media_trip_search_update ON media.media;
+CREATE TRIGGER media_trip_search_update
+    AFTER INSERT OR DELETE OR UPDATE OF trip_id, location_id, visibility ON media.media
+    FOR EACH ROW EXECUTE FUNCTION trips.media_search_trigger();
+
+UPDATE trips.trips
+SET search_vector = trips.trip_search_vector(language, name, description, trips.trip_places(trip_id));
+
+CREATE INDEX IF NOT EXISTS idx_trips_search_vector ON trips.trips USING GIN (search_vector);
@@ -0,0 +1,64 @@
+-- Convert trips.start_date and trips.end_date from free-form text to DATE.
+-- Values that are not ISO 8601 dates become NULL and are recorded in
+-- trips.trip_date_migration_report so they can be fixed by hand.
+CREATE TABLE IF NOT EXISTS trips.trip_date_migration_report (
+    trip_id     INTEGER NOT NULL,
+    column_name VARCHAR(16) NOT NULL,
+    raw_value   TEXT NOT NULL,
+    reported_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE OR REPLACE FUNCTION trips.try_parse_iso_date(value TEXT) RETURNS DATE AS $$
+BEGIN
+    IF value IS NULL OR btrim(value) = '' THEN
+        RETURN NULL;
+    END IF;
+    -- YYYY-MM-DD, optionally followed by an ISO 8601 time
+    IF btrim(value) !~ '^\d{4}-\d{2}-\d{2}([T ].*)?$' THEN
+        RETURN NULL;
+    END IF;
+    RETURN substring(btrim(value) FROM 1 FOR 10)::date;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+INSERT INTO trips.trip_date_migration_report (trip_id, column_name, raw_value)
+SELECT trip_id, 'start_date', start_date
+FROM trips.trips
+WHERE btrim(COALESCE(start_date, '')) <> '' AND trips.try_parse_iso_date(start_date) IS NULL
+UNION ALL
+SELECT trip_id, 'end_date', end_date
+FROM trips.trips
+WHERE btrim(COALESCE(end_date, '')) <> '' AND trips.try_parse_iso_date(end_date) IS NULL;
+
+DO $$
+DECLARE
+    unparsed RECORD;
+BEGIN
+    FOR unparsed IN SELECT * FROM trips.trip_date_migration_report ORDER BY trip_id LOOP
+        RAISE NOTICE 'trip %: % value % is not an ISO 8601 date and was cleared',
+            unparsed.trip_id, unparsed.column_name, quote_literal(unparsed.raw_value);
+    END LOOP;
+END
+$$;
+
+DROP INDEX IF EXISTS trips.idx_trips_start_date;
+
+ALTER TABLE trips.trips
+    ALTER COLUMN start_date TYPE DATE USING trips.try_parse_iso_date(start_date),
+    ALTER COLUMN end_date TYPE DATE USING trips.try_parse_iso_date(end_date);
+
+-- Rows whose end precedes their start cannot be fixed automatically either
+INSERT INTO trips.trip_date_migration_report (trip_id, column_name, raw_value)
+SELECT trip_id, 'end_date', end_date::text
+FROM trips.trips
+WHERE end_date < start_date;
+
+UPDATE trips.trips SET end_date = NULL WHERE end_date < start_date;
+
+ALTER TABLE trips.trips
+    ADD CONSTRAINT trips_dates_ordered CHECK (end_date IS NULL OR start_date IS NULL OR start_date <= end_date);
+
+CREATE INDEX IF NOT EXISTS idx_trips_start_date ON trips.trips ((COALESCE(start_date, '-infinity'::date)), trip_id);
+CREATE INDEX IF NOT EXISTS idx_trips_date_range ON trips.trips (start_date, end_date);
@@ -0,0 +1,13 @@
+ALTER TABLE trips.trips ADD COLUMN IF NOT EXISTS category VARCHAR(32)
+    CHECK (category IS NULL OR category IN ('road_trip', 'hiking', 'city_break', 'beach', 'backpacking',
+        'camping', 'cruise', 'ski', 'cultural', 'food', 'adventure', 'family'));
+
+CREATE INDEX IF NOT EXISTS idx_trips_category ON trips.trips (category);
+
+CREATE TABLE IF NOT EXISTS trips.trip_tags (
+    trip_id INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    tag     VARCHAR(40) NOT NULL,
+    PRIMARY KEY (trip_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_tags_tag ON trips.trip_tags (tag);
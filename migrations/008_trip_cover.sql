@@ -0,0 +1,6 @@
+ALTER TABLE trips.trips ADD COLUMN IF NOT EXISTS cover_media_id BIGINT
+    REFERENCES media.media (media_id) ON DELETE SET NULL;
+
+-- Automatic covers pick the earliest geotagged photo of each trip
+CREATE INDEX IF NOT EXISTS idx_media_trip_capture ON media.media (trip_id, capture_date, media_id)
+    WHERE type = 'photo';
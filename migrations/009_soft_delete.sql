@@ -0,0 +1,21 @@
+-- Trashed rows keep their data until the purge job removes them once the
+-- retention window has passed.
+ALTER TABLE trips.trips ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE media.media ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE albums.album_trips ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_trips_deleted_at ON trips.trips (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_media_deleted_at ON media.media (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Trashed media no longer make a trip findable by its places
+CREATE OR REPLACE FUNCTION trips.trip_places(p_trip_id INTEGER) RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(DISTINCT concat_ws(' ', l.city, l.country), ' '), '')
+    FROM media.media m
+    JOIN locations.locations l ON l.location_id = m.location_id
+    WHERE m.trip_id = p_trip_id AND m.visibility = 'PUBLIC' AND m.deleted_at IS NULL
+$$ LANGUAGE sql STABLE;
+
+DROP TRIGGER IF EXISTS media_trip_search_update ON media.media;
+CREATE TRIGGER media_trip_search_update
+    AFTER INSERT OR DELETE OR UPDATE OF trip_id, location_id, visibility, deleted_at ON media.media
+    FOR EACH ROW EXECUTE FUNCTION trips.media_search_trigger();
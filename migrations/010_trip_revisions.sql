@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS trips.trip_revisions (
+    revision_id BIGSERIAL PRIMARY KEY,
+    trip_id     INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    revision    INTEGER NOT NULL,
+    user_id     INTEGER NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    changes     JSONB NOT NULL,
+    -- Set when the revision undid an earlier one
+    revert_of   INTEGER,
+    UNIQUE (trip_id, revision)
+);
@@ -0,0 +1,4 @@
+-- Optimistic concurrency: every update bumps the version, which clients send
+-- back in If-Match.
+ALTER TABLE trips.trips ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE media.media ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
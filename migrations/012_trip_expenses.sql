@@ -0,0 +1,37 @@
+-- Amounts are stored in minor units (cents). Exchange rates give how many
+-- units of a currency one EUR buys; EUR itself is implicit with a rate of 1.
+CREATE TABLE IF NOT EXISTS trips.exchange_rates (
+    currency   CHAR(3) PRIMARY KEY,
+    rate       NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    updated_by INTEGER NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS trips.trip_budgets (
+    trip_id  INTEGER PRIMARY KEY REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    amount   BIGINT NOT NULL CHECK (amount >= 0),
+    currency CHAR(3) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS trips.trip_expenses (
+    expense_id   BIGSERIAL PRIMARY KEY,
+    trip_id      INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    amount       BIGINT NOT NULL CHECK (amount > 0),
+    currency     CHAR(3) NOT NULL,
+    category     VARCHAR(32) NOT NULL
+                 CHECK (category IN ('accommodation', 'transport', 'food', 'activities', 'shopping', 'fees', 'other')),
+    description  TEXT,
+    expense_date DATE NOT NULL,
+    paid_by      INTEGER NOT NULL,
+    created_by   INTEGER NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_expenses_trip ON trips.trip_expenses (trip_id, expense_date);
+
+-- The members an expense is split among, in equal shares
+CREATE TABLE IF NOT EXISTS trips.trip_expense_splits (
+    expense_id BIGINT NOT NULL REFERENCES trips.trip_expenses (expense_id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL,
+    PRIMARY KEY (expense_id, user_id)
+);
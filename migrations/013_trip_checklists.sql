@@ -0,0 +1,42 @@
+-- Per-trip checklists (packing lists, to-dos) with ordered items
+CREATE TABLE IF NOT EXISTS trips.checklists (
+    checklist_id BIGSERIAL PRIMARY KEY,
+    trip_id      INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    title        VARCHAR(255) NOT NULL,
+    position     INTEGER NOT NULL,
+    created_by   INTEGER NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklists_trip ON trips.checklists (trip_id, position);
+
+CREATE TABLE IF NOT EXISTS trips.checklist_items (
+    item_id      BIGSERIAL PRIMARY KEY,
+    checklist_id BIGINT NOT NULL REFERENCES trips.checklists (checklist_id) ON DELETE CASCADE,
+    trip_id      INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    position     INTEGER NOT NULL,
+    text         VARCHAR(500) NOT NULL,
+    checked      BOOLEAN NOT NULL DEFAULT false,
+    assignee_id  INTEGER,
+    checked_by   INTEGER,
+    checked_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklist_items_checklist ON trips.checklist_items (checklist_id, position);
+
+-- Reusable checklists owned by a user, copied into trips on demand
+CREATE TABLE IF NOT EXISTS trips.checklist_templates (
+    template_id BIGSERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    title       VARCHAR(255) NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklist_templates_user ON trips.checklist_templates (user_id);
+
+CREATE TABLE IF NOT EXISTS trips.checklist_template_items (
+    template_id BIGINT NOT NULL REFERENCES trips.checklist_templates (template_id) ON DELETE CASCADE,
+    position    INTEGER NOT NULL,
+    text        VARCHAR(500) NOT NULL,
+    PRIMARY KEY (template_id, position)
+);
@@ -0,0 +1,24 @@
+-- Dated journal entries of a trip. The body is Markdown, sanitized when it is
+-- rendered, and may show the trip's media inline as ![caption](media:<media_id>).
+CREATE TABLE IF NOT EXISTS trips.journal_entries (
+    entry_id    BIGSERIAL PRIMARY KEY,
+    trip_id     INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    author_id   INTEGER NOT NULL,
+    entry_date  DATE NOT NULL,
+    title       VARCHAR(255),
+    body        TEXT NOT NULL,
+    location_id BIGINT REFERENCES locations.locations (location_id),
+    visibility  VARCHAR(16) NOT NULL CHECK (visibility IN ('PUBLIC', 'FRIENDS', 'PRIVATE')),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_trip ON trips.journal_entries (trip_id, entry_date);
+
+-- The media referenced by an entry's body, kept in sync on every write
+CREATE TABLE IF NOT EXISTS trips.journal_entry_media (
+    entry_id BIGINT NOT NULL REFERENCES trips.journal_entries (entry_id) ON DELETE CASCADE,
+    media_id BIGINT NOT NULL REFERENCES media.media (media_id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (entry_id, media_id)
+);
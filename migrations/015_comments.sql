@@ -0,0 +1,21 @@
+-- Threaded comments on trips and on their media. root_id is the top-level
+-- comment of the thread (its own ID for top-level comments), so a page of
+-- threads is loaded with a single query. Deleted comments keep their place
+-- in the thread with their body removed.
+CREATE TABLE IF NOT EXISTS trips.comments (
+    comment_id BIGSERIAL PRIMARY KEY,
+    trip_id    INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    media_id   BIGINT REFERENCES media.media (media_id) ON DELETE CASCADE,
+    parent_id  BIGINT REFERENCES trips.comments (comment_id) ON DELETE CASCADE,
+    root_id    BIGINT REFERENCES trips.comments (comment_id) ON DELETE CASCADE,
+    author_id  INTEGER NOT NULL,
+    body       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    edited_at  TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    deleted_by INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_trip ON trips.comments (trip_id, comment_id) WHERE media_id IS NULL AND parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_media ON trips.comments (media_id, comment_id) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_root ON trips.comments (root_id);
@@ -0,0 +1,30 @@
+-- GPS tracks recorded separately from the media (GPX, TCX or FIT files) and
+-- their points. Media without GPS get a position interpolated from them.
+CREATE TABLE IF NOT EXISTS trips.trip_tracks (
+    track_id    BIGSERIAL PRIMARY KEY,
+    trip_id     INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    name        VARCHAR(255) NOT NULL,
+    format      VARCHAR(8) NOT NULL CHECK (format IN ('gpx', 'tcx', 'fit')),
+    started_at  TIMESTAMPTZ,
+    ended_at    TIMESTAMPTZ,
+    point_count INTEGER NOT NULL,
+    distance_km DOUBLE PRECISION NOT NULL DEFAULT 0,
+    uploaded_by INTEGER NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_tracks_trip ON trips.trip_tracks (trip_id);
+
+CREATE TABLE IF NOT EXISTS trips.trip_track_points (
+    track_id  BIGINT NOT NULL REFERENCES trips.trip_tracks (track_id) ON DELETE CASCADE,
+    trip_id   INTEGER NOT NULL REFERENCES trips.trips (trip_id) ON DELETE CASCADE,
+    seq       INTEGER NOT NULL,
+    time      TIMESTAMPTZ,
+    latitude  DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    elevation DOUBLE PRECISION,
+    PRIMARY KEY (track_id, seq)
+);
+
+-- Interpolation looks up the points around a capture time
+CREATE INDEX IF NOT EXISTS idx_trip_track_points_time ON trips.trip_track_points (trip_id, time) WHERE time IS NOT NULL;
@@ -0,0 +1,17 @@
+-- Background imports of ZIP archives (our own trip archives or Google Takeout
+-- photo exports). trip_ids lists the trips created so far.
+CREATE TABLE IF NOT EXISTS trips.import_jobs (
+    job_id          BIGSERIAL PRIMARY KEY,
+    user_id         INTEGER NOT NULL,
+    source          VARCHAR(16) NOT NULL CHECK (source IN ('nostos', 'takeout')),
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    total_items     INTEGER NOT NULL DEFAULT 0,
+    processed_items INTEGER NOT NULL DEFAULT 0,
+    failed_items    INTEGER NOT NULL DEFAULT 0,
+    trip_ids        JSONB NOT NULL DEFAULT '[]',
+    error           TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_user ON trips.import_jobs (user_id, job_id);
@@ -0,0 +1,12 @@
+-- Secret tokens of the per-user iCalendar feeds. Only a SHA-256 hash of each
+-- token is stored, the token itself is shown once when it is created.
+CREATE TABLE IF NOT EXISTS trips.calendar_tokens (
+    token_id     BIGSERIAL PRIMARY KEY,
+    user_id      INTEGER NOT NULL,
+    token_hash   CHAR(64) NOT NULL UNIQUE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_tokens_user ON trips.calendar_tokens (user_id);
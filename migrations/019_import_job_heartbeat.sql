@@ -0,0 +1,6 @@
+-- Running imports renew heartbeat_at; an unfinished import whose heartbeat
+-- stopped was interrupted along with the process running it.
+ALTER TABLE trips.import_jobs ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_unfinished ON trips.import_jobs (heartbeat_at)
+    WHERE status IN ('pending', 'running');
@@ -0,0 +1,14 @@
+-- One unfinished import per user. Jobs left over from concurrent starts are
+-- failed first, keeping the most recent one.
+UPDATE trips.import_jobs j
+SET status = 'failed', error = 'superseded by a newer import', finished_at = now()
+WHERE j.status IN ('pending', 'running')
+  AND EXISTS (
+    SELECT 1 FROM trips.import_jobs newer
+    WHERE newer.user_id = j.user_id
+      AND newer.status IN ('pending', 'running')
+      AND newer.job_id > j.job_id
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_import_jobs_one_active
+    ON trips.import_jobs (user_id) WHERE status IN ('pending', 'running');
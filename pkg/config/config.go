@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +20,10 @@ type Config struct {
 	AuthServiceUrl    string
 	ProfileServiceUrl string
 	NatsUrl           string
+	// TrashRetention is how long deleted trips and media can be restored
+	TrashRetention time.Duration
+	// AdminUserIDs may manage service-wide data such as exchange rates
+	AdminUserIDs []uint
 }
 
 func LoadConfig() *Config {
@@ -35,5 +42,30 @@ func LoadConfig() *Config {
 		AuthServiceUrl:    os.Getenv("AUTH_SERVICE_URL"),
 		ProfileServiceUrl: os.Getenv("PROFILE_SERVICE_URL"),
 		NatsUrl:           os.Getenv("NATS_URL"),
+		TrashRetention:    durationInDays(os.Getenv("TRASH_RETENTION_DAYS")),
+		AdminUserIDs:      userIDs(os.Getenv("ADMIN_USER_IDS")),
 	}
 }
+
+// durationInDays returns zero for empty or invalid values so callers fall
+// back to their default.
+func durationInDays(value string) time.Duration {
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// userIDs parses a comma-separated list of user IDs, skipping invalid entries.
+func userIDs(value string) []uint {
+	var ids []uint
+	for _, field := range strings.Split(value, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(field), 10, 64)
+		if err != nil || id == 0 {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids
+}